@@ -0,0 +1,179 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+type BreakerState int
+
+const (
+	StateClosed BreakerState = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerPolicy configures the circuit breaker kept per downstream host.
+// After FailureThreshold consecutive failures (connection errors and 5xx
+// responses) the breaker opens and calls fail fast with
+// errors.CircuitOpenError. Once CoolDown has elapsed it lets HalfOpenProbes
+// calls through: a success closes it again, a failure re-opens it.
+type BreakerPolicy struct {
+	// FailureThreshold defaults to 5.
+	FailureThreshold int
+	// CoolDown defaults to 30s.
+	CoolDown time.Duration
+	// HalfOpenProbes is the number of concurrent trial calls allowed while
+	// half-open. Defaults to 1.
+	HalfOpenProbes int
+	// OnStateChange is called on every transition, e.g. for logging or
+	// metrics. It must not block.
+	OnStateChange func(host string, from BreakerState, to BreakerState)
+}
+
+func (p BreakerPolicy) withDefaults() BreakerPolicy {
+	if p.FailureThreshold <= 0 {
+		p.FailureThreshold = 5
+	}
+	if p.CoolDown <= 0 {
+		p.CoolDown = 30 * time.Second
+	}
+	if p.HalfOpenProbes <= 0 {
+		p.HalfOpenProbes = 1
+	}
+	return p
+}
+
+type CircuitBreaker struct {
+	host   string
+	policy BreakerPolicy
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+func NewCircuitBreaker(host string, policy BreakerPolicy) *CircuitBreaker {
+	return &CircuitBreaker{host: host, policy: policy.withDefaults(), now: time.Now}
+}
+
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Allow reports whether a call may go through. Every allowed call must be
+// followed by Record, or by Release when it has no outcome.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if b.now().Sub(b.openedAt) < b.policy.CoolDown {
+			return false
+		}
+		b.transition(StateHalfOpen)
+		b.probes = 1
+		return true
+	case StateHalfOpen:
+		if b.probes >= b.policy.HalfOpenProbes {
+			return false
+		}
+		b.probes++
+		return true
+	}
+	return true
+}
+
+func (b *CircuitBreaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateHalfOpen:
+		b.probes--
+		if success {
+			b.failures = 0
+			b.transition(StateClosed)
+		} else {
+			b.open()
+		}
+	case StateClosed:
+		if success {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.policy.FailureThreshold {
+			b.open()
+		}
+	}
+}
+
+// Release gives back a call allowed by Allow without recording an outcome,
+// e.g. one the caller cancelled.
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+func (b *CircuitBreaker) open() {
+	b.openedAt = b.now()
+	b.probes = 0
+	b.transition(StateOpen)
+}
+
+func (b *CircuitBreaker) transition(to BreakerState) {
+	from := b.state
+	if from == to {
+		return
+	}
+	b.state = to
+	if b.policy.OnStateChange != nil {
+		b.policy.OnStateChange(b.host, from, to)
+	}
+}
+
+// breakers hands out one CircuitBreaker per host.
+type breakers struct {
+	policy BreakerPolicy
+
+	mu     sync.Mutex
+	byHost map[string]*CircuitBreaker
+}
+
+func newBreakers(policy BreakerPolicy) *breakers {
+	return &breakers{policy: policy, byHost: map[string]*CircuitBreaker{}}
+}
+
+func (b *breakers) get(host string) *CircuitBreaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	breaker, ok := b.byHost[host]
+	if !ok {
+		breaker = NewCircuitBreaker(host, b.policy)
+		b.byHost[host] = breaker
+	}
+	return breaker
+}
@@ -0,0 +1,150 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// fakeClock is moved forward by hand instead of sleeping through cool downs.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestBreaker(policy BreakerPolicy) (*CircuitBreaker, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	breaker := NewCircuitBreaker("downstream", policy)
+	breaker.now = clock.Now
+	return breaker, clock
+}
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	breaker, _ := newTestBreaker(BreakerPolicy{FailureThreshold: 3})
+
+	for i := 0; i < 2; i++ {
+		breaker.Allow()
+		breaker.Record(false)
+	}
+	breaker.Allow()
+	breaker.Record(true)
+	if breaker.State() != StateClosed {
+		t.Fatal("a success did not reset the failure count")
+	}
+
+	for i := 0; i < 3; i++ {
+		breaker.Allow()
+		breaker.Record(false)
+	}
+	if breaker.State() != StateOpen {
+		t.Fatalf("state = %s", breaker.State())
+	}
+	if breaker.Allow() {
+		t.Error("open breaker let a call through")
+	}
+}
+
+func TestBreakerHalfOpensAfterCoolDown(t *testing.T) {
+	var transitions []BreakerState
+	breaker, clock := newTestBreaker(BreakerPolicy{
+		FailureThreshold: 1,
+		CoolDown:         10 * time.Second,
+		OnStateChange: func(_ string, _ BreakerState, to BreakerState) {
+			transitions = append(transitions, to)
+		},
+	})
+	breaker.Allow()
+	breaker.Record(false)
+
+	clock.Advance(9 * time.Second)
+	if breaker.Allow() {
+		t.Fatal("allowed before the cool down")
+	}
+	clock.Advance(time.Second)
+	if !breaker.Allow() {
+		t.Fatal("probe not allowed after the cool down")
+	}
+	if breaker.Allow() {
+		t.Error("second concurrent probe allowed")
+	}
+
+	breaker.Record(false)
+	if breaker.State() != StateOpen {
+		t.Fatalf("failed probe left the breaker %s", breaker.State())
+	}
+	clock.Advance(10 * time.Second)
+	breaker.Allow()
+	breaker.Record(true)
+
+	want := []BreakerState{StateOpen, StateHalfOpen, StateOpen, StateHalfOpen, StateClosed}
+	if len(transitions) != len(want) {
+		t.Fatalf("transitions = %v", transitions)
+	}
+	for i := range want {
+		if transitions[i] != want[i] {
+			t.Fatalf("transitions = %v, want %v", transitions, want)
+		}
+	}
+}
+
+func TestBreakerReleaseFreesTheProbe(t *testing.T) {
+	breaker, clock := newTestBreaker(BreakerPolicy{FailureThreshold: 1, CoolDown: time.Second})
+	breaker.Allow()
+	breaker.Record(false)
+	clock.Advance(time.Second)
+
+	breaker.Allow()
+	breaker.Release()
+	if breaker.State() != StateHalfOpen {
+		t.Fatalf("state = %s", breaker.State())
+	}
+	if !breaker.Allow() {
+		t.Error("released probe still counted")
+	}
+}
+
+func TestCancelledCallsAreNotBreakerFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	c := New(WithBaseURL(server.URL), WithCircuitBreaker(BreakerPolicy{FailureThreshold: 1}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if res := Get[struct{}](ctx, c, "/"); !res.IsError() {
+		t.Fatal("cancelled call succeeded")
+	}
+
+	host, _ := url.Parse(server.URL)
+	if state := c.BreakerState(host.Host); state != StateClosed {
+		t.Errorf("state = %s after a call the caller gave up on", state)
+	}
+}
+
+func TestClientTimeoutIsABreakerFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	c := New(WithBaseURL(server.URL), WithTimeout(10*time.Millisecond), WithCircuitBreaker(BreakerPolicy{FailureThreshold: 1}))
+
+	if res := Get[struct{}](context.Background(), c, "/"); !res.IsError() {
+		t.Fatal("timed out call succeeded")
+	}
+
+	host, _ := url.Parse(server.URL)
+	if state := c.BreakerState(host.Host); state != StateOpen {
+		t.Errorf("state = %s after the client timed out", state)
+	}
+}
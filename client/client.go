@@ -0,0 +1,376 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+	"github.com/samber/mo"
+)
+
+// Client calls other services over HTTP/JSON. The typed helpers Get, Post,
+// Put, Patch and Delete return mo.Result like our handlers do, with the REQ
+// faults of the errors package on failure. Called with the context of a
+// RequestCtx, they forward its call id (and more, see WithPropagation).
+type Client struct {
+	httpClient  *http.Client
+	timeout     time.Duration
+	baseURL     string
+	headers     http.Header
+	rawResponse bool
+	retry       *RetryPolicy
+	breakers    *breakers
+	propagation Propagation
+}
+
+func New(opts ...Option) *Client {
+	c := &Client{
+		httpClient:  &http.Client{},
+		timeout:     DefaultTimeout,
+		headers:     http.Header{},
+		propagation: DefaultPropagation,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// StatusError is the cause of the fault returned for a non-2xx response.
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected response status %d: %s", e.StatusCode, truncate(e.Body, 512))
+}
+
+func Get[Resp any](ctx context.Context, c *Client, path string, opts ...RequestOption) mo.Result[*Resp] {
+	return do[Resp](ctx, c, http.MethodGet, path, nil, opts)
+}
+
+func Delete[Resp any](ctx context.Context, c *Client, path string, opts ...RequestOption) mo.Result[*Resp] {
+	return do[Resp](ctx, c, http.MethodDelete, path, nil, opts)
+}
+
+// Post, Put and Patch send body as JSON, or no body at all when it is nil.
+func Post[Req any, Resp any](ctx context.Context, c *Client, path string, body *Req, opts ...RequestOption) mo.Result[*Resp] {
+	return do[Resp](ctx, c, http.MethodPost, path, requestBody(body), opts)
+}
+
+func Put[Req any, Resp any](ctx context.Context, c *Client, path string, body *Req, opts ...RequestOption) mo.Result[*Resp] {
+	return do[Resp](ctx, c, http.MethodPut, path, requestBody(body), opts)
+}
+
+func Patch[Req any, Resp any](ctx context.Context, c *Client, path string, body *Req, opts ...RequestOption) mo.Result[*Resp] {
+	return do[Resp](ctx, c, http.MethodPatch, path, requestBody(body), opts)
+}
+
+// requestBody keeps a nil *Req from becoming a non-nil any, which would be
+// sent as a "null" body.
+func requestBody[Req any](body *Req) any {
+	if body == nil {
+		return nil
+	}
+	return body
+}
+
+func do[Resp any](ctx context.Context, c *Client, method string, path string, body any, opts []RequestOption) mo.Result[*Resp] {
+	o := newRequestOptions(c, opts)
+
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return mo.Err[*Resp](generateRequestError(method, err))
+		}
+	}
+
+	var respBody []byte
+	var f fault.Fault
+	if o.retry == nil {
+		respBody, f = c.execute(ctx, method, path, payload, o)
+	} else {
+		respBody, f = c.executeWithRetry(ctx, method, path, payload, o, o.retry.withDefaults())
+	}
+	if f != nil {
+		return mo.Err[*Resp](f)
+	}
+	return decode[Resp](c.rawResponse, respBody)
+}
+
+// attemptError is the outcome of a failed attempt, before it becomes a fault.
+type attemptError struct {
+	err  error
+	resp *http.Response
+	// f is set when the failure is not worth retrying whatever the policy.
+	f fault.Fault
+}
+
+func (c *Client) execute(ctx context.Context, method string, path string, payload []byte, o requestOptions) ([]byte, fault.Fault) {
+	respBody, attemptErr := c.attempt(ctx, c.timeout, method, path, payload, o)
+	if attemptErr != nil {
+		if attemptErr.f != nil {
+			return nil, attemptErr.f
+		}
+		if f, ok := attemptErr.err.(fault.Fault); ok {
+			return nil, f
+		}
+		return nil, errors.ExecutingGetRequestError(attemptErr.err)
+	}
+	return respBody, nil
+}
+
+// executeWithRetry attempts the call until it succeeds or the policy gives
+// up. A downstream fault is returned as is when retrying stops, so that its
+// code and status reach the caller rather than those of a retries fault.
+func (c *Client) executeWithRetry(ctx context.Context, method string, path string, payload []byte, o requestOptions, policy RetryPolicy) ([]byte, fault.Fault) {
+	// callCtx bounds the waits by the budget; each attempt is bounded by
+	// its timeout, so that running out of budget counts against the host
+	// but the caller hanging up does not.
+	callCtx := ctx
+	var deadline time.Time
+	if policy.Budget > 0 {
+		deadline = time.Now().Add(policy.Budget)
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	for attempt := 1; ; attempt++ {
+		timeout := c.timeout
+		if !deadline.IsZero() {
+			remaining := time.Until(deadline)
+			if remaining <= 0 {
+				return nil, errors.ExecutingRequestRetriesError(attempt-1, context.DeadlineExceeded)
+			}
+			if timeout <= 0 || remaining < timeout {
+				timeout = remaining
+			}
+		}
+		respBody, attemptErr := c.attempt(ctx, timeout, method, path, payload, o)
+		if attemptErr == nil {
+			return respBody, nil
+		}
+		if attemptErr.f != nil {
+			return nil, attemptErr.f
+		}
+
+		retryable := false
+		wait := policy.backoff(attempt)
+		if attemptErr.resp != nil {
+			retryable = policy.retryableStatus(method, attemptErr.resp.StatusCode)
+			if after, ok := retryAfter(attemptErr.resp); ok && after > wait {
+				wait = after
+				retryable = retryable && after <= policy.MaxBackoff
+			}
+		} else {
+			retryable = policy.retryableError(callCtx, method, attemptErr.err)
+		}
+
+		outOfBudget := !deadline.IsZero() && time.Now().Add(wait).After(deadline)
+		if !retryable || attempt >= policy.MaxAttempts || outOfBudget {
+			if f, ok := attemptErr.err.(fault.Fault); ok {
+				return nil, f
+			}
+			return nil, errors.ExecutingRequestRetriesError(attempt, attemptErr.err)
+		}
+		if err := sleep(callCtx, wait); err != nil {
+			return nil, errors.ExecutingRequestRetriesError(attempt, err)
+		}
+	}
+}
+
+// BreakerState returns the state of the circuit breaker of a downstream
+// host, StateClosed when the client has no circuit breaker.
+func (c *Client) BreakerState(host string) BreakerState {
+	if c.breakers == nil {
+		return StateClosed
+	}
+	return c.breakers.get(host).State()
+}
+
+// attempt sends the request once, through the host's circuit breaker when
+// configured, and reads the response body, all within timeout: the client
+// timeout, or what is left of the retry budget when that is shorter.
+//
+// A call the caller cancelled, or whose deadline passed, says nothing about
+// the health of the host and is not recorded by the breaker. The timeout is
+// not the caller's: an attempt running into it counts as failed.
+func (c *Client) attempt(ctx context.Context, timeout time.Duration, method string, path string, payload []byte, o requestOptions) ([]byte, *attemptError) {
+	callerCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req, f := c.newRequest(ctx, method, path, payload, o)
+	if f != nil {
+		return nil, &attemptError{f: f}
+	}
+	if c.breakers == nil {
+		return c.send(req)
+	}
+
+	breaker := c.breakers.get(req.URL.Host)
+	if !breaker.Allow() {
+		return nil, &attemptError{f: errors.CircuitOpenError(req.URL.Host)}
+	}
+	respBody, attemptErr := c.send(req)
+	if attemptErr != nil && callerCtx.Err() != nil {
+		breaker.Release()
+		return respBody, attemptErr
+	}
+	breaker.Record(attemptErr == nil || (attemptErr.resp != nil && attemptErr.resp.StatusCode < 500))
+	return respBody, attemptErr
+}
+
+func (c *Client) send(req *http.Request) ([]byte, *attemptError) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &attemptError{err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, &attemptError{f: errors.ReadingResponseBodyError(err)}
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		statusErr := &StatusError{StatusCode: resp.StatusCode, Body: respBody}
+		attemptErr := &attemptError{err: statusErr, resp: resp}
+		if f, ok := decodeFault(statusErr); ok {
+			attemptErr.err = f
+		}
+		return nil, attemptErr
+	}
+	return respBody, nil
+}
+
+// decodeFault rebuilds the fault of a routeutils.ErrorEnvelope body. Bodies
+// without an error code, e.g. from services outside this library, stay a
+// plain StatusError.
+func decodeFault(statusErr *StatusError) (fault.Fault, bool) {
+	var env routeutils.ErrorEnvelope
+	if err := json.Unmarshal(statusErr.Body, &env); err != nil || env.Error.ErrorCode == "" {
+		return nil, false
+	}
+	return errors.DownstreamFault(
+		fault.ErrorCode(env.Error.ErrorCode),
+		env.Error.Data,
+		statusErr.StatusCode,
+		env.Error.Message,
+		statusErr,
+	), true
+}
+
+// DownstreamFault returns the fault a called service responded with. The
+// typed helpers return it as is, keeping the downstream error code and
+// status; DownstreamFault also finds it in the cause chain of a fault that
+// wraps it. A handler can return it to pass the code on to its own caller.
+func DownstreamFault(err error) (fault.Fault, bool) {
+	for err != nil {
+		f, ok := err.(fault.Fault)
+		if !ok {
+			return nil, false
+		}
+		if _, ok := f.Cause().(*StatusError); ok {
+			return f, true
+		}
+		err = f.Cause()
+	}
+	return nil, false
+}
+
+func newRequestOptions(c *Client, opts []RequestOption) requestOptions {
+	o := requestOptions{headers: http.Header{}, query: url.Values{}, retry: c.retry}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (c *Client) newRequest(ctx context.Context, method string, path string, payload []byte, o requestOptions) (*http.Request, fault.Fault) {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, generateRequestError(method, err)
+	}
+	if len(o.query) > 0 {
+		query := req.URL.Query()
+		for key, values := range o.query {
+			for _, value := range values {
+				query.Add(key, value)
+			}
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, values := range c.headers {
+		req.Header[key] = values
+	}
+	for key, values := range o.headers {
+		req.Header[key] = values
+	}
+	c.propagation.propagate(ctx, req)
+	return req, nil
+}
+
+func generateRequestError(method string, cause error) fault.Fault {
+	if method == http.MethodGet || method == http.MethodDelete {
+		return errors.GenerateGetRequestError(cause)
+	}
+	return errors.GeneratePostRequestError(cause)
+}
+
+// envelope is the success body written by routeutils.HandleRequest.
+type envelope struct {
+	Data json.RawMessage `json:"data"`
+}
+
+func decode[Resp any](rawResponse bool, body []byte) mo.Result[*Resp] {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return mo.Ok[*Resp](nil)
+	}
+
+	data := body
+	if !rawResponse {
+		var env envelope
+		if err := json.Unmarshal(body, &env); err != nil {
+			return mo.Err[*Resp](errors.DecodingResponseBodyError(err))
+		}
+		if len(env.Data) == 0 || string(env.Data) == "null" {
+			return mo.Ok[*Resp](nil)
+		}
+		data = env.Data
+	}
+
+	var resp Resp
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return mo.Err[*Resp](errors.UnmarshalResponseError(err))
+	}
+	return mo.Ok(&resp)
+}
+
+func truncate(b []byte, max int) string {
+	if len(b) <= max {
+		return string(b)
+	}
+	return string(b[:max]) + "..."
+}
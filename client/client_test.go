@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+)
+
+type okResponse struct {
+	Ok bool `json:"ok"`
+}
+
+func TestNilBodyIsNotSent(t *testing.T) {
+	var body []byte
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		contentType = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+	c := New(WithBaseURL(server.URL))
+
+	if _, err := Post[okResponse, okResponse](context.Background(), c, "/", nil).Get(); err != nil {
+		t.Fatal(err)
+	}
+	if len(body) != 0 || contentType != "" {
+		t.Errorf("sent body %q with content type %q", body, contentType)
+	}
+}
+
+func TestWithTimeoutLeavesHTTPClientAlone(t *testing.T) {
+	httpClient := &http.Client{}
+	New(WithHTTPClient(httpClient), WithTimeout(time.Millisecond))
+	New(WithTimeout(time.Millisecond))
+
+	if httpClient.Timeout != 0 || http.DefaultClient.Timeout != 0 {
+		t.Errorf("timeouts changed to %s and %s", httpClient.Timeout, http.DefaultClient.Timeout)
+	}
+}
+
+func TestWithTimeoutAppliesWhateverTheOptionOrder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	for name, c := range map[string]*Client{
+		"timeout first": New(WithTimeout(10*time.Millisecond), WithHTTPClient(&http.Client{}), WithBaseURL(server.URL)),
+		"timeout last":  New(WithHTTPClient(&http.Client{}), WithTimeout(10*time.Millisecond), WithBaseURL(server.URL)),
+	} {
+		start := time.Now()
+		if res := Get[okResponse](context.Background(), c, "/"); !res.IsError() {
+			t.Errorf("%s: call succeeded", name)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("%s: timed out after %s", name, elapsed)
+		}
+	}
+}
+
+func TestDownstreamFaultKeepsCodeAndStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		_ = json.NewEncoder(w).Encode(routeutils.ErrorEnvelope{Error: routeutils.ErrorBody{
+			Message:   "order already paid",
+			ErrorCode: "ORD0000000001",
+		}})
+	}))
+	defer server.Close()
+
+	for name, c := range map[string]*Client{
+		"single attempt": New(WithBaseURL(server.URL)),
+		"with retries":   New(WithBaseURL(server.URL), WithRetry(fastRetry)),
+	} {
+		_, err := Get[okResponse](context.Background(), c, "/").Get()
+		f, ok := err.(fault.Fault)
+		if !ok {
+			t.Fatalf("%s: %T is not a fault", name, err)
+		}
+		if f.Code() != "ORD0000000001" || errors.FaultStatusCode(f) != http.StatusConflict || f.ResponseErrType() != errors.Conflict {
+			t.Errorf("%s: fault %s, status %d, type %s", name, f.Code(), errors.FaultStatusCode(f), f.ResponseErrType())
+		}
+		if downstream, ok := DownstreamFault(err); !ok || downstream != f {
+			t.Errorf("%s: DownstreamFault = %v, %v", name, downstream, ok)
+		}
+	}
+}
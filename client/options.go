@@ -0,0 +1,110 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const DefaultTimeout = 30 * time.Second
+
+type Option func(*Client)
+
+// WithBaseURL is prepended to the path of every request.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient replaces the http.Client, e.g. to set a custom transport.
+// The client is used as is: WithTimeout applies on top of its own Timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout bounds every attempt of a call, reading the response body
+// included. Defaults to DefaultTimeout; zero leaves attempts unbounded.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithHeader sets a header sent with every request.
+func WithHeader(key string, value string) Option {
+	return func(c *Client) {
+		c.headers.Set(key, value)
+	}
+}
+
+// WithRawResponse decodes response bodies straight into the response type
+// instead of expecting the {"data": ...} envelope HandleRequest produces,
+// for calls to services not built on this library.
+func WithRawResponse() Option {
+	return func(c *Client) {
+		c.rawResponse = true
+	}
+}
+
+// WithRetry retries failed calls according to policy. Without it every call
+// is attempted once.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = &policy
+	}
+}
+
+// WithCircuitBreaker guards every downstream host with its own circuit
+// breaker configured by policy.
+func WithCircuitBreaker(policy BreakerPolicy) Option {
+	return func(c *Client) {
+		c.breakers = newBreakers(policy.withDefaults())
+	}
+}
+
+// WithPropagation sets what is forwarded from the request being handled.
+// Defaults to DefaultPropagation.
+func WithPropagation(propagation Propagation) Option {
+	return func(c *Client) {
+		c.propagation = propagation
+	}
+}
+
+type requestOptions struct {
+	headers http.Header
+	query   url.Values
+	retry   *RetryPolicy
+}
+
+type RequestOption func(*requestOptions)
+
+// WithRequestHeader sets a header on a single request.
+func WithRequestHeader(key string, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.headers.Set(key, value)
+	}
+}
+
+// WithQuery adds a query parameter to a single request.
+func WithQuery(key string, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.query.Add(key, value)
+	}
+}
+
+// WithRequestRetry overrides the client's retry policy for a single request.
+func WithRequestRetry(policy RetryPolicy) RequestOption {
+	return func(o *requestOptions) {
+		o.retry = &policy
+	}
+}
+
+// WithoutRetry attempts a single request once whatever the client's policy.
+func WithoutRetry() RequestOption {
+	return func(o *requestOptions) {
+		o.retry = nil
+	}
+}
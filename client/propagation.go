@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Propagation selects what an outbound call forwards from the request being
+// handled, taken from a context built by (*routeutils.RequestCtx).Context.
+type Propagation struct {
+	// CallId forwards the call id as x-call-id so traces stitch together.
+	CallId bool
+	// TraceContext forwards the current span as the W3C traceparent and
+	// tracestate headers.
+	TraceContext bool
+	// Authorization forwards the caller's Authorization header.
+	Authorization bool
+	// Headers lists further incoming headers forwarded as they are.
+	Headers []string
+}
+
+// DefaultPropagation forwards the call id and the trace context.
+var DefaultPropagation = Propagation{CallId: true, TraceContext: true}
+
+// propagate copies the configured values from ctx onto req, without
+// overriding headers set explicitly on the client or the request.
+func (p Propagation) propagate(ctx context.Context, req *http.Request) {
+	if p.CallId && req.Header.Get(routeutils.CallIdHeader) == "" {
+		if callId, ok := routeutils.CallIdFromContext(ctx); ok && uuid.UUID(callId) != uuid.Nil {
+			req.Header.Set(routeutils.CallIdHeader, uuid.UUID(callId).String())
+		}
+	}
+
+	if p.TraceContext && req.Header.Get("traceparent") == "" {
+		propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	}
+
+	incoming, ok := routeutils.IncomingHeaderFromContext(ctx)
+	if !ok {
+		return
+	}
+	forward := p.Headers
+	if p.Authorization {
+		forward = append([]string{"Authorization"}, forward...)
+	}
+	for _, name := range forward {
+		if req.Header.Get(name) != "" {
+			continue
+		}
+		if values := incoming.Values(name); len(values) > 0 {
+			req.Header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+}
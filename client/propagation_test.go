@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/PrathamSkilltelligent/pmgingo/types"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// incomingContext is the context of a request being handled, with a call id,
+// a sampled span and an Authorization header.
+func incomingContext(t *testing.T) (context.Context, types.CallId, trace.SpanContext) {
+	t.Helper()
+	callId := types.CallId(uuid.New())
+	span := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		SpanID:     trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), span)
+	ctx = routeutils.ContextWithIncomingHeader(ctx, http.Header{
+		"Authorization": {"Bearer caller-token"},
+		"X-Tenant":      {"acme"},
+	})
+	return routeutils.ContextWithCallId(ctx, callId), callId, span
+}
+
+func TestPropagate(t *testing.T) {
+	ctx, callId, span := incomingContext(t)
+	traceparent := "00-" + span.TraceID().String() + "-" + span.SpanID().String() + "-01"
+
+	tests := []struct {
+		name        string
+		propagation Propagation
+		preset      http.Header
+		want        map[string]string
+	}{
+		{
+			name:        "default",
+			propagation: DefaultPropagation,
+			want: map[string]string{
+				routeutils.CallIdHeader: uuid.UUID(callId).String(),
+				"traceparent":           traceparent,
+				"Authorization":         "",
+				"X-Tenant":              "",
+			},
+		},
+		{
+			name:        "authorization",
+			propagation: Propagation{Authorization: true, Headers: []string{"x-tenant"}},
+			want: map[string]string{
+				routeutils.CallIdHeader: "",
+				"traceparent":           "",
+				"Authorization":         "Bearer caller-token",
+				"X-Tenant":              "acme",
+			},
+		},
+		{
+			name:        "explicit headers kept",
+			propagation: Propagation{CallId: true, Authorization: true},
+			preset: http.Header{
+				http.CanonicalHeaderKey(routeutils.CallIdHeader): {"preset-call"},
+				"Authorization": {"Bearer service-token"},
+			},
+			want: map[string]string{
+				routeutils.CallIdHeader: "preset-call",
+				"Authorization":         "Bearer service-token",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "http://downstream", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, values := range tt.preset {
+				req.Header[key] = values
+			}
+
+			tt.propagation.propagate(ctx, req)
+			for name, want := range tt.want {
+				if got := req.Header.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPropagateWithoutIncomingRequest(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "http://downstream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Propagation{CallId: true, TraceContext: true, Authorization: true}.propagate(context.Background(), req)
+	if len(req.Header) != 0 {
+		t.Errorf("headers set without a request: %v", req.Header)
+	}
+}
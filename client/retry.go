@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	stderrors "errors"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy retries connection errors and retryable statuses with
+// exponential backoff and jitter. Non-idempotent methods (POST, PATCH) are
+// only retried when the request cannot have reached the server: a failed
+// dial or a 429.
+type RetryPolicy struct {
+	// MaxAttempts counts the first attempt too. Defaults to 3.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. Defaults to 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps a single wait. Defaults to 5s. A response asking to
+	// retry later than that with Retry-After is not retried.
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after each retry. Defaults to 2.
+	Multiplier float64
+	// Jitter shortens each wait by a random fraction of up to Jitter, in
+	// [0, 1]. Defaults to 0.2 when zero; negative disables it.
+	Jitter float64
+	// Budget bounds the time spent on a call, retries and waits included:
+	// an attempt is cut short when the budget runs out, and a retry that
+	// cannot start within it is not attempted. Zero means no budget beyond
+	// MaxAttempts.
+	Budget time.Duration
+	// RetryStatuses defaults to DefaultRetryStatuses.
+	RetryStatuses []int
+}
+
+// DefaultRetryStatuses are 429 and the 5xx statuses of a failure that may be
+// transient. Apart from 429 they are only retried for idempotent methods.
+var DefaultRetryStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = 100 * time.Millisecond
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = 5 * time.Second
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = 2
+	}
+	switch {
+	case p.Jitter == 0:
+		p.Jitter = 0.2
+	case p.Jitter < 0:
+		p.Jitter = 0
+	case p.Jitter > 1:
+		p.Jitter = 1
+	}
+	if p.RetryStatuses == nil {
+		p.RetryStatuses = DefaultRetryStatuses
+	}
+	return p
+}
+
+// backoff returns the wait before retry number retry (1 based).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	wait := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(retry-1))
+	if wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	// #nosec G404 -- jitter does not need a secure source
+	wait -= wait * p.Jitter * rand.Float64()
+	return time.Duration(wait)
+}
+
+func (p RetryPolicy) retryableStatus(method string, status int) bool {
+	if status == http.StatusTooManyRequests {
+		return containsStatus(p.RetryStatuses, status)
+	}
+	return isIdempotent(method) && containsStatus(p.RetryStatuses, status)
+}
+
+func (p RetryPolicy) retryableError(ctx context.Context, method string, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var opErr *net.OpError
+	if stderrors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return isIdempotent(method)
+}
+
+func containsStatus(statuses []int, status int) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	header := resp.Header.Get("Retry-After")
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sleep waits for d unless ctx is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer answers status for the first failures requests, then 200.
+func flakyServer(t *testing.T, failures int32, status int, header http.Header) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"ok": true}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+var fastRetry = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+
+func TestRetryRecoversFromRetryableStatus(t *testing.T) {
+	server, calls := flakyServer(t, 2, http.StatusServiceUnavailable, nil)
+	c := New(WithBaseURL(server.URL), WithRetry(fastRetry))
+
+	resp, err := Get[okResponse](context.Background(), c, "/").Get()
+	if err != nil || !resp.Ok {
+		t.Fatalf("resp %v, err %v", resp, err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("%d calls", calls.Load())
+	}
+}
+
+func TestRetrySkipsNonIdempotentMethods(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusServiceUnavailable, nil)
+	c := New(WithBaseURL(server.URL), WithRetry(fastRetry))
+
+	if res := Post[okResponse, okResponse](context.Background(), c, "/", &okResponse{}); !res.IsError() {
+		t.Fatal("POST retried after a 503")
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d calls", calls.Load())
+	}
+}
+
+func TestRetryHonoursShortRetryAfter(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"0"}})
+	c := New(WithBaseURL(server.URL), WithRetry(fastRetry))
+
+	if _, err := Get[okResponse](context.Background(), c, "/").Get(); err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("%d calls", calls.Load())
+	}
+}
+
+func TestRetryGivesUpOnRetryAfterBeyondMaxBackoff(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"3600"}})
+	c := New(WithBaseURL(server.URL), WithRetry(fastRetry))
+
+	start := time.Now()
+	if res := Get[okResponse](context.Background(), c, "/"); !res.IsError() {
+		t.Fatal("waited an hour")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("gave up after %s", elapsed)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("%d calls", calls.Load())
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 3 * time.Second, Jitter: -1}.withDefaults()
+	for retry, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 3 * time.Second, 10: 3 * time.Second} {
+		if got := policy.backoff(retry); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", retry, got, want)
+		}
+	}
+}
+
+func TestRetryBudgetBoundsSlowAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	policy := fastRetry
+	policy.Budget = 50 * time.Millisecond
+	c := New(WithBaseURL(server.URL), WithRetry(policy))
+
+	start := time.Now()
+	if res := Get[okResponse](context.Background(), c, "/"); !res.IsError() {
+		t.Fatal("call succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("a %s budget took %s", policy.Budget, elapsed)
+	}
+}
+
+func TestRetryIdempotentInternalServerError(t *testing.T) {
+	server, calls := flakyServer(t, 1, http.StatusInternalServerError, nil)
+	c := New(WithBaseURL(server.URL), WithRetry(fastRetry))
+
+	if _, err := Get[okResponse](context.Background(), c, "/").Get(); err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("%d calls", calls.Load())
+	}
+}
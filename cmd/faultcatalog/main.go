@@ -0,0 +1,53 @@
+// Command faultcatalog exports the error codes of this library as JSON or
+// Markdown. Applications registering their own codes get the same output by
+// calling errors.DefaultRegistry.Catalog() with errors.WriteCatalogJSON or
+// errors.WriteCatalogMarkdown from a command of their own, after their
+// registrations have run.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+)
+
+func main() {
+	format := flag.String("format", "json", "output format: json or markdown")
+	out := flag.String("out", "", "output file, stdout when empty")
+	flag.Parse()
+
+	if err := run(*format, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "faultcatalog:", err)
+		os.Exit(1)
+	}
+}
+
+func run(format string, out string) (err error) {
+	var write func(io.Writer, []errors.CatalogEntry) error
+	switch format {
+	case "json":
+		write = errors.WriteCatalogJSON
+	case "markdown", "md":
+		write = errors.WriteCatalogMarkdown
+	default:
+		return fmt.Errorf("unknown format %q", format)
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		file, err := os.Create(out) // #nosec G304
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if closeErr := file.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = file
+	}
+	return write(w, errors.DefaultRegistry.Catalog())
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunKeepsTheOutputOnUnknownFormat(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "catalog.json")
+	if err := os.WriteFile(out, []byte("previous catalog"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run("jsno", out); err == nil {
+		t.Fatal("unknown format accepted")
+	}
+	if content, _ := os.ReadFile(out); string(content) != "previous catalog" {
+		t.Errorf("output overwritten with %q", content)
+	}
+}
+
+func TestRunWritesTheCatalog(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "catalog.md")
+	if err := run("markdown", out); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(out); err != nil || info.Size() == 0 {
+		t.Errorf("catalog not written: %v", err)
+	}
+}
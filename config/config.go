@@ -0,0 +1,267 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/samber/mo"
+)
+
+/** Struct Tags **/
+const (
+	// KeyTag names a field's configuration key. Nested structs join their
+	// keys with a dot, e.g. `config:"db"` + `config:"host"` => "db.host".
+	// Untagged fields use the lower-cased field name; "-" skips the field.
+	KeyTag = "config"
+	// EnvTag overrides the environment variable name derived from the key.
+	EnvTag = "env"
+	// DefaultTag holds the value used when no source sets the key.
+	DefaultTag = "default"
+	// ValidateTag holds go-playground/validator rules, as with gin bindings.
+	ValidateTag = "validate"
+)
+
+type options struct {
+	file      string
+	envPrefix string
+	args      []string
+	lookupEnv func(string) (string, bool)
+}
+
+type Option func(*options)
+
+// WithFile reads the configuration from a YAML (.yaml, .yml) or TOML (.toml)
+// file.
+func WithFile(path string) Option {
+	return func(o *options) {
+		o.file = path
+	}
+}
+
+// WithEnvPrefix prefixes every derived environment variable name, e.g.
+// prefix "APP" maps key "db.host" to APP_DB_HOST.
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithArgs parses command line flags named after the keys, e.g.
+// -db.host=localhost, and a bare -debug for a bool key. Other arguments,
+// such as flags the application parses itself, are ignored. Typically
+// os.Args[1:].
+func WithArgs(args []string) Option {
+	return func(o *options) {
+		o.args = args
+	}
+}
+
+// WithLookupEnv replaces os.LookupEnv as the environment source.
+func WithLookupEnv(lookupEnv func(string) (string, bool)) Option {
+	return func(o *options) {
+		o.lookupEnv = lookupEnv
+	}
+}
+
+// FieldError describes one configuration key that is missing or invalid.
+type FieldError struct {
+	Key    string
+	Source string
+	Reason string
+}
+
+func (e FieldError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("%s: %s", e.Key, e.Reason)
+	}
+	return fmt.Sprintf("%s (%s): %s", e.Key, e.Source, e.Reason)
+}
+
+// FieldErrors is the cause of the ConfigError returned by Load; it lists
+// every key that failed rather than only the first one.
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, f := range e {
+		msgs = append(msgs, f.Error())
+	}
+	return "invalid configuration: " + strings.Join(msgs, "; ")
+}
+
+func (e FieldErrors) has(key string) bool {
+	for _, f := range e {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Load fills a T from, in increasing order of precedence, `default` tags, the
+// configuration file, environment variables and command line flags, then
+// validates it against its `validate` tags. Any failure is returned as an
+// errors.ConfigError fault.
+func Load[T any](opts ...Option) mo.Result[*T] {
+	o := options{lookupEnv: os.LookupEnv}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var t T
+	root := reflect.ValueOf(&t).Elem()
+	if root.Kind() != reflect.Struct {
+		return mo.Err[*T](errors.ConfigError(fmt.Errorf("config: %T is not a struct", t)))
+	}
+	fields := collectFields(root, "")
+
+	fileValues := map[string]any{}
+	if o.file != "" {
+		values, err := readFile(o.file)
+		if err != nil {
+			return mo.Err[*T](errors.ConfigError(err))
+		}
+		fileValues = values
+	}
+	flagValues, err := parseFlags(fields, o.args)
+	if err != nil {
+		return mo.Err[*T](errors.ConfigError(err))
+	}
+
+	var fieldErrs FieldErrors
+	for _, f := range fields {
+		raw, source, found := resolve(f, o, fileValues, flagValues)
+		if !found {
+			continue
+		}
+		if err := setValue(f.value, raw); err != nil {
+			fieldErrs = append(fieldErrs, FieldError{Key: f.key, Source: source, Reason: err.Error()})
+		}
+	}
+	// A key that could not be converted is left zero, don't report it twice.
+	for _, validationErr := range validate(&t) {
+		if !fieldErrs.has(validationErr.Key) {
+			fieldErrs = append(fieldErrs, validationErr)
+		}
+	}
+
+	if len(fieldErrs) > 0 {
+		return mo.Err[*T](errors.ConfigError(fieldErrs))
+	}
+	return mo.Ok(&t)
+}
+
+type field struct {
+	key   string
+	env   string
+	def   *string
+	value reflect.Value
+}
+
+func collectFields(v reflect.Value, prefix string) []field {
+	var fields []field
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		key := keyName(sf)
+		if key == "" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct && !isLeaf(fv.Type()) {
+			fields = append(fields, collectFields(fv, key)...)
+			continue
+		}
+
+		f := field{key: key, env: sf.Tag.Get(EnvTag), value: fv}
+		if def, ok := sf.Tag.Lookup(DefaultTag); ok {
+			f.def = &def
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+func keyName(sf reflect.StructField) string {
+	key := sf.Tag.Get(KeyTag)
+	switch key {
+	case "-":
+		return ""
+	case "":
+		return strings.ToLower(sf.Name)
+	}
+	return key
+}
+
+func envName(prefix string, f field) string {
+	if f.env != "" {
+		return f.env
+	}
+	name := strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(f.key))
+	if prefix != "" {
+		name = strings.ToUpper(prefix) + "_" + name
+	}
+	return name
+}
+
+// resolve returns the raw value of the highest precedence source setting the
+// key and a description of that source.
+func resolve(f field, o options, fileValues map[string]any, flagValues map[string]string) (any, string, bool) {
+	if val, ok := flagValues[f.key]; ok {
+		return val, "flag -" + f.key, true
+	}
+	env := envName(o.envPrefix, f)
+	if val, ok := o.lookupEnv(env); ok {
+		return val, "env " + env, true
+	}
+	if val, ok := lookupPath(fileValues, f.key); ok {
+		return val, "file " + o.file, true
+	}
+	if f.def != nil {
+		return *f.def, "default", true
+	}
+	return nil, "", false
+}
+
+func validate(t any) FieldErrors {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.SetTagName(ValidateTag)
+	v.RegisterTagNameFunc(keyName)
+
+	err := v.Struct(t)
+	if err == nil {
+		return nil
+	}
+	validationErrs, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return FieldErrors{{Key: "", Reason: err.Error()}}
+	}
+
+	fieldErrs := make(FieldErrors, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		// Namespace is "<Type>.<key>.<key>", drop the type name.
+		key := fe.Namespace()
+		if i := strings.Index(key, "."); i >= 0 {
+			key = key[i+1:]
+		}
+		reason := fmt.Sprintf("failed %q validation", fe.Tag())
+		if fe.Param() != "" {
+			reason = fmt.Sprintf("failed %q validation", fe.Tag()+"="+fe.Param())
+		}
+		if fe.Tag() == "required" {
+			reason = "missing"
+		}
+		fieldErrs = append(fieldErrs, FieldError{Key: key, Reason: reason})
+	}
+	return fieldErrs
+}
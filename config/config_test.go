@@ -0,0 +1,183 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+)
+
+type dbConfig struct {
+	Host    string        `config:"host" default:"localhost"`
+	Port    int           `config:"port" default:"5432" validate:"min=1,max=65535"`
+	Timeout time.Duration `config:"timeout" default:"5s"`
+}
+
+type appConfig struct {
+	Name     string   `config:"name" validate:"required"`
+	Debug    bool     `config:"debug"`
+	Password string   `config:"password" env:"APP_DB_PASSWORD"`
+	Tags     []string `config:"tags"`
+	DB       dbConfig `config:"db"`
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func env(vars map[string]string) Option {
+	return WithLookupEnv(func(key string) (string, bool) {
+		val, ok := vars[key]
+		return val, ok
+	})
+}
+
+func fieldErrors(t *testing.T, err error) FieldErrors {
+	t.Helper()
+	f, ok := err.(fault.Fault)
+	if !ok || f.Code() != errors.ErrAppConfigError {
+		t.Fatalf("error = %v, want a ConfigError", err)
+	}
+	fieldErrs, ok := f.Cause().(FieldErrors)
+	if !ok {
+		t.Fatalf("cause = %v, want FieldErrors", f.Cause())
+	}
+	return fieldErrs
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "app.yaml", "name: file\ndb:\n  host: file-host\n  port: 6000\n")
+
+	tests := []struct {
+		name string
+		opts []Option
+		host string
+		port int
+	}{
+		{"defaults", []Option{env(nil), WithArgs([]string{"-name=x"})}, "localhost", 5432},
+		{"file over default", []Option{WithFile(file), env(nil)}, "file-host", 6000},
+		{"env over file", []Option{WithFile(file), WithEnvPrefix("app"), env(map[string]string{"APP_DB_HOST": "env-host"})}, "env-host", 6000},
+		{"flag over env", []Option{
+			WithFile(file),
+			WithEnvPrefix("app"),
+			env(map[string]string{"APP_DB_HOST": "env-host", "APP_DB_PORT": "7000"}),
+			WithArgs([]string{"-db.host", "flag-host"}),
+		}, "flag-host", 7000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load[appConfig](tt.opts...).Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.DB.Host != tt.host || cfg.DB.Port != tt.port {
+				t.Errorf("db = %s:%d, want %s:%d", cfg.DB.Host, cfg.DB.Port, tt.host, tt.port)
+			}
+			if cfg.DB.Timeout != 5*time.Second {
+				t.Errorf("timeout = %s", cfg.DB.Timeout)
+			}
+		})
+	}
+}
+
+func TestLoadFileFormats(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"app.yaml", "name: svc\ntags: [a, b]\ndb:\n  host: db.internal\n  timeout: 2s\n"},
+		{"app.yml", "name: svc\ntags: [a, b]\ndb:\n  host: db.internal\n  timeout: 2s\n"},
+		{"app.toml", "name = \"svc\"\ntags = [\"a\", \"b\"]\n[db]\nhost = \"db.internal\"\ntimeout = \"2s\"\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load[appConfig](WithFile(writeFile(t, tt.name, tt.content)), env(nil)).Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Name != "svc" || cfg.DB.Host != "db.internal" || cfg.DB.Timeout != 2*time.Second {
+				t.Errorf("config = %+v", cfg)
+			}
+			if strings.Join(cfg.Tags, ",") != "a,b" {
+				t.Errorf("tags = %v", cfg.Tags)
+			}
+		})
+	}
+
+	if _, err := Load[appConfig](WithFile(writeFile(t, "app.json", "{}")), env(nil)).Get(); err == nil {
+		t.Error("unsupported format accepted")
+	}
+}
+
+func TestLoadFlags(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		debug bool
+		tags  string
+	}{
+		{"bare bool", []string{"-name=svc", "-debug"}, true, ""},
+		{"explicit bool", []string{"-name=svc", "--debug=false"}, false, ""},
+		{"unknown flags skipped", []string{"-verbose", "-listen", ":8080", "-name", "svc", "-debug", "-tags=a,b", "extra"}, true, "a,b"},
+		{"stops at terminator", []string{"-name=svc", "--", "-debug"}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load[appConfig](WithArgs(tt.args), env(nil)).Get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Name != "svc" || cfg.Debug != tt.debug || strings.Join(cfg.Tags, ",") != tt.tags {
+				t.Errorf("config = %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestLoadAggregatesErrors(t *testing.T) {
+	_, err := Load[appConfig](
+		WithArgs([]string{"-db.timeout=soon"}),
+		env(map[string]string{"DB_PORT": "70000"}),
+	).Get()
+
+	fieldErrs := fieldErrors(t, err)
+	want := map[string]string{
+		"name":       "missing",
+		"db.port":    `failed "max=65535" validation`,
+		"db.timeout": "invalid duration",
+	}
+	if len(fieldErrs) != len(want) {
+		t.Fatalf("errors = %v", fieldErrs)
+	}
+	for _, fe := range fieldErrs {
+		if want[fe.Key] != fe.Reason {
+			t.Errorf("%s: reason %q, want %q", fe.Key, fe.Reason, want[fe.Key])
+		}
+	}
+}
+
+func TestLoadErrorsHideValues(t *testing.T) {
+	_, err := Load[appConfig](
+		WithArgs([]string{"-name=svc", "-db.port=hunter2"}),
+		env(map[string]string{"DEBUG": "s3cr3t"}),
+	).Get()
+
+	fieldErrs := fieldErrors(t, err)
+	if len(fieldErrs) != 2 {
+		t.Fatalf("errors = %v", fieldErrs)
+	}
+	for _, secret := range []string{"hunter2", "s3cr3t"} {
+		if strings.Contains(fieldErrs.Error(), secret) {
+			t.Errorf("%q leaked: %v", secret, fieldErrs)
+		}
+	}
+}
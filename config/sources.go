@@ -0,0 +1,228 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+var timeType = reflect.TypeOf(time.Time{})
+
+// isLeaf reports whether a struct type is set as a single value rather than
+// walked field by field.
+func isLeaf(t reflect.Type) bool {
+	return t == timeType
+}
+
+func readFile(path string) (map[string]any, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	values := map[string]any{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &values)
+	case ".toml":
+		err = toml.Unmarshal(content, &values)
+	default:
+		return nil, fmt.Errorf("unsupported config file format %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// lookupPath walks nested maps following a dotted key.
+func lookupPath(values map[string]any, key string) (any, bool) {
+	var current any = values
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// flagValue records the raw value of a configuration flag. Bool fields take
+// a bare -key as -key=true, as flag.Bool does.
+type flagValue struct {
+	value  string
+	isBool bool
+}
+
+func (f *flagValue) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *flagValue) Set(s string) error {
+	f.value = s
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	return f.isBool
+}
+
+// parseFlags parses the flags named after configuration keys. Any other
+// argument, e.g. a flag the application defines itself, is skipped.
+func parseFlags(fields []field, args []string) (map[string]string, error) {
+	values := map[string]string{}
+	if len(args) == 0 {
+		return values, nil
+	}
+
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	raw := make(map[string]*flagValue, len(fields))
+	for _, f := range fields {
+		raw[f.key] = &flagValue{isBool: f.value.Kind() == reflect.Bool}
+		fs.Var(raw[f.key], f.key, "")
+	}
+	if err := fs.Parse(knownFlags(raw, args)); err != nil {
+		return nil, fmt.Errorf("parsing flags: %w", err)
+	}
+	fs.Visit(func(fl *flag.Flag) {
+		values[fl.Name] = raw[fl.Name].value
+	})
+	return values, nil
+}
+
+// knownFlags keeps the arguments setting one of the known flags, with the
+// value following a non-bool -key.
+func knownFlags(known map[string]*flagValue, args []string) []string {
+	var kept []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		f, ok := known[name]
+		if !ok {
+			continue
+		}
+		kept = append(kept, arg)
+		if !hasValue && !f.isBool && i+1 < len(args) {
+			i++
+			kept = append(kept, args[i])
+		}
+	}
+	return kept
+}
+
+// setValue converts raw, a string from env/flags/defaults or a decoded file
+// value, into the field's type.
+func setValue(v reflect.Value, raw any) error {
+	if v.Kind() == reflect.Slice {
+		var items []string
+		switch r := raw.(type) {
+		case []any:
+			for _, item := range r {
+				items = append(items, fmt.Sprint(item))
+			}
+		case string:
+			for _, item := range strings.Split(r, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+		default:
+			return fmt.Errorf("expected a list of %s", v.Type().Elem())
+		}
+
+		slice := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setScalar(slice.Index(i), item); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	}
+
+	switch r := raw.(type) {
+	case string:
+		return setScalar(v, r)
+	case time.Time:
+		return setScalar(v, r.Format(time.RFC3339Nano))
+	case map[string]any, []any:
+		return fmt.Errorf("expected a single %s", v.Type())
+	}
+	return setScalar(v, fmt.Sprint(raw))
+}
+
+// setScalar converts s into the field's type. Its errors name the type but
+// never quote s, which may be a secret.
+func setScalar(v reflect.Value, s string) error {
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration")
+		}
+		v.SetInt(int64(d))
+		return nil
+	case v.Type() == timeType:
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return fmt.Errorf("invalid time, expected RFC 3339")
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid bool")
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s", v.Kind())
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s", v.Kind())
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid %s", v.Kind())
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported field type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// CatalogEntry is the published description of an error code, for client
+// SDKs and documentation.
+type CatalogEntry struct {
+	Code         string            `json:"code"`
+	Component    string            `json:"component"`
+	ResponseType string            `json:"responseType"`
+	HttpStatus   int               `json:"httpStatus"`
+	Messages     map[string]string `json:"messages"`
+}
+
+// Catalog lists every registered code, sorted by code.
+func (r *Registry) Catalog() []CatalogEntry {
+	defs := r.Definitions()
+	entries := make([]CatalogEntry, 0, len(defs))
+	for _, def := range defs {
+		status := def.HttpStatus
+		if status == 0 {
+			status = StatusCode(def.ResponseType)
+		}
+		entries = append(entries, CatalogEntry{
+			Code:         def.Code.String(),
+			Component:    string(def.Component),
+			ResponseType: string(def.ResponseType),
+			HttpStatus:   status,
+			Messages:     def.Messages(),
+		})
+	}
+	return entries
+}
+
+func WriteCatalogJSON(w io.Writer, entries []CatalogEntry) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]any{
+		"errors": entries,
+	})
+}
+
+// WriteCatalogMarkdown writes the catalog as a Markdown table with one
+// message column per locale, DefaultLocale first.
+func WriteCatalogMarkdown(w io.Writer, entries []CatalogEntry) error {
+	locales := catalogLocales(entries)
+
+	var b strings.Builder
+	b.WriteString("| Code | Component | Response type | HTTP status |")
+	for _, locale := range locales {
+		fmt.Fprintf(&b, " Message (%s) |", locale)
+	}
+	b.WriteString("\n|---|---|---|---|")
+	for range locales {
+		b.WriteString("---|")
+	}
+	b.WriteString("\n")
+
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %d |",
+			entry.Code, escapeCell(entry.Component), escapeCell(entry.ResponseType), entry.HttpStatus)
+		for _, locale := range locales {
+			fmt.Fprintf(&b, " %s |", escapeCell(entry.Messages[locale]))
+		}
+		b.WriteString("\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func catalogLocales(entries []CatalogEntry) []string {
+	seen := map[string]struct{}{}
+	for _, entry := range entries {
+		for locale := range entry.Messages {
+			seen[locale] = struct{}{}
+		}
+	}
+	delete(seen, DefaultLocale)
+
+	locales := make([]string, 0, len(seen))
+	for locale := range seen {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return append([]string{DefaultLocale}, locales...)
+}
+
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
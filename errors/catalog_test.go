@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func testCatalog(t *testing.T) []CatalogEntry {
+	t.Helper()
+	registry := NewRegistry()
+	for _, def := range []FaultDefinition{
+		{
+			Code:         "ORD0000000010",
+			Component:    ErrService,
+			ResponseType: Conflict,
+			Message:      "order {{.order_id}} is already paid",
+			Translations: map[string]string{"fr": "la commande {{.order_id}} est déjà payée"},
+		},
+		{
+			Code:         "ORD0000000001",
+			Component:    ErrService,
+			ResponseType: NotFound,
+			Message:      "order not found | archived",
+		},
+		{
+			Code:         "ORD0000000020",
+			Component:    ErrRepo,
+			ResponseType: InternalServer,
+			HttpStatus:   507,
+			Message:      "out of storage",
+			Translations: map[string]string{"de": "kein Speicher"},
+		},
+	} {
+		if _, err := registry.Register(def); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return registry.Catalog()
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs, got:\n%s", name, got)
+	}
+}
+
+func TestCatalogJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCatalogJSON(&buf, testCatalog(t)); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "catalog.json", buf.Bytes())
+}
+
+func TestCatalogMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCatalogMarkdown(&buf, testCatalog(t)); err != nil {
+		t.Fatal(err)
+	}
+	assertGolden(t, "catalog.md", buf.Bytes())
+}
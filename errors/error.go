@@ -17,12 +17,23 @@ const (
 
 /** Response Error Type Constants **/
 const (
-	BadRequest     fault.ResponseErrType = "BadRequest"
-	Forbidden      fault.ResponseErrType = "Forbidden"
-	NotFound       fault.ResponseErrType = "NotFound"
-	AlreadyExists  fault.ResponseErrType = "AlreadyExists"
-	InternalServer fault.ResponseErrType = "InternalServerError"
-	Unauthorized   fault.ResponseErrType = "Unauthorized"
+	BadRequest           fault.ResponseErrType = "BadRequest"
+	Forbidden            fault.ResponseErrType = "Forbidden"
+	NotFound             fault.ResponseErrType = "NotFound"
+	AlreadyExists        fault.ResponseErrType = "AlreadyExists"
+	InternalServer       fault.ResponseErrType = "InternalServerError"
+	Unauthorized         fault.ResponseErrType = "Unauthorized"
+	MethodNotAllowed     fault.ResponseErrType = "MethodNotAllowed"
+	RequestTimeout       fault.ResponseErrType = "RequestTimeout"
+	Conflict             fault.ResponseErrType = "Conflict"
+	PreconditionFailed   fault.ResponseErrType = "PreconditionFailed"
+	PayloadTooLarge      fault.ResponseErrType = "PayloadTooLarge"
+	UnsupportedMediaType fault.ResponseErrType = "UnsupportedMediaType"
+	UnprocessableEntity  fault.ResponseErrType = "UnprocessableEntity"
+	TooManyRequests      fault.ResponseErrType = "TooManyRequests"
+	BadGateway           fault.ResponseErrType = "BadGateway"
+	ServiceUnavailable   fault.ResponseErrType = "ServiceUnavailable"
+	GatewayTimeout       fault.ResponseErrType = "GatewayTimeout"
 )
 
 /** Error Code Constants **/
@@ -53,6 +64,9 @@ const (
 	ErrUnmarshalResponse              fault.ErrorCode = "REQ0000000120"
 	ErrAuthTokenNotFound              fault.ErrorCode = "REQ0000000130" // #nosec G101
 	ErrInvalidAuthToken               fault.ErrorCode = "REQ0000000140" // #nosec G101
+	ErrCircuitOpen                    fault.ErrorCode = "REQ0000000150"
+	ErrRequestTimeout                 fault.ErrorCode = "REQ0000000160"
+	ErrRateLimitExceeded              fault.ErrorCode = "REQ0000000170"
 
 	// db error codes
 	ErrRecordNotFound fault.ErrorCode = "REPO0000000000"
@@ -64,34 +78,45 @@ const (
 	ErrOrgNotFound fault.ErrorCode = "ORG0000000000"
 )
 
+// builtinFaults defines the library's own codes. They are registered in
+// DefaultRegistry and back localFaultCache.
+var builtinFaults = []FaultDefinition{
+	{Code: ErrParamNotFound, Component: ErrController, ResponseType: BadRequest, Message: "Error parameter {{.name}} not found"},
+	{Code: ErrParamInvalid, Component: ErrController, ResponseType: BadRequest, Message: "Error invalid parameter {{.name}}"},
+	{Code: ErrParamSourceInvalid, Component: ErrController, ResponseType: BadRequest, Message: "Error invalid parameter source {{.source}}"},
+	{Code: ErrTypeCast, Component: ErrApplication, ResponseType: BadRequest, Message: "Error failed to cast {{.name}} having value {{.val}} to {{.datatype}}"},
+	{Code: ErrGetValFromGinCtxFailed, Component: ErrController, ResponseType: InternalServer, Message: "Error failed to get value from gin context for key {{.name}}"},
+	{Code: ErrAppConfigError, Component: ErrApplication, ResponseType: InternalServer, Message: "Error invalid application configuration"},
+	{Code: ErrInternalServerError, Component: ErrApplication, ResponseType: InternalServer, Message: "Internal Server Error. Please Contact Admin."},
+	{Code: ErrDatabaseInternalError, Component: ErrRepo, ResponseType: InternalServer, Message: "Error database internal error"},
+	{Code: ErrFailedToExtractDataFromRequest, Component: ErrController, ResponseType: BadRequest, Message: "Error failed to extract data from request"},
+	{Code: ErrGetCallerIdFromHeader, Component: ErrController, ResponseType: BadRequest, Message: "Error failed to get call id from header {{.name}}"},
+	{Code: ErrGetUserIdFromGinCtx, Component: ErrController, ResponseType: BadRequest, Message: "Error failed to get user id {{.name}} from gin context"},
+	{Code: ErrGetUnixTimeFromQueryParam, Component: ErrController, ResponseType: BadRequest, Message: "Error failed to get unix time from query parameter"},
+	{Code: ErrInvalidRequestBody, Component: ErrController, ResponseType: BadRequest, Message: "Error invalid request body"},
+	{Code: ErrGeneratePostRequest, Component: ErrController, ResponseType: InternalServer, Message: "Error failed to generate post request"},
+	{Code: ErrGenerateGetRequest, Component: ErrController, ResponseType: InternalServer, Message: "Error failed to generate get request"},
+	{Code: ErrExecutingRequest, Component: ErrController, ResponseType: InternalServer, Message: "Error failed to execute request{{if .attempts}} after {{.attempts}} attempts{{end}}"},
+	{Code: ErrReadingRespBody, Component: ErrController, ResponseType: InternalServer, Message: "Error failed to read response body"},
+	{Code: ErrDecodingResponseBody, Component: ErrController, ResponseType: InternalServer, Message: "Error failed to decode response body"},
+	{Code: ErrUnmarshalResponse, Component: ErrController, ResponseType: InternalServer, Message: "Error failed to unmarshal response"},
+	{Code: ErrRecordNotFound, Component: ErrController, ResponseType: InternalServer, Message: "Error record {{.id}} not found"},
+	{Code: ErrUserNotFound, Component: ErrController, ResponseType: BadRequest, Message: "Error user {{.user_id}} not found"},
+	{Code: ErrOrgNotFound, Component: ErrController, ResponseType: BadRequest, Message: "Error org {{.org_id}} not found"},
+	{Code: ErrGetOrgIdFromPathParam, Component: ErrController, ResponseType: BadRequest, Message: "Error failed to get org id from path parameter"},
+	{Code: ErrAuthTokenNotFound, Component: ErrController, ResponseType: Unauthorized, Message: "Error auth token not found"},
+	{Code: ErrInvalidAuthToken, Component: ErrController, ResponseType: Unauthorized, Message: "Error invalid auth token"},
+	{Code: ErrCircuitOpen, Component: ErrService, ResponseType: ServiceUnavailable, Message: "Error circuit breaker for {{.host}} is open"},
+	{Code: ErrRequestTimeout, Component: ErrService, ResponseType: GatewayTimeout, Message: "Error request{{if .route}} to {{.route}}{{end}} timed out"},
+	{Code: ErrRateLimitExceeded, Component: ErrService, ResponseType: TooManyRequests, Message: "Error rate limit exceeded{{if .retry_after}}, retry in {{.retry_after}} seconds{{end}}"},
+}
+
 // Initialize your basicfaultcache here
 func buildBasicFaults() map[fault.ErrorCode]fault.BasicFault {
 	var localBasicFaults = map[fault.ErrorCode]fault.BasicFault{}
-	localBasicFaults[ErrParamNotFound] = fault.NewBasicFault(ErrParamNotFound).SetComponent(ErrController).SetResponseType(BadRequest)
-	localBasicFaults[ErrParamInvalid] = fault.NewBasicFault(ErrParamInvalid).SetComponent(ErrController).SetResponseType(BadRequest)
-	localBasicFaults[ErrParamSourceInvalid] = fault.NewBasicFault(ErrParamSourceInvalid).SetComponent(ErrController).SetResponseType(BadRequest)
-	localBasicFaults[ErrTypeCast] = fault.NewBasicFault(ErrTypeCast).SetComponent(ErrApplication).SetResponseType(BadRequest)
-	localBasicFaults[ErrGetValFromGinCtxFailed] = fault.NewBasicFault(ErrGetValFromGinCtxFailed).SetComponent(ErrController).SetResponseType(InternalServer)
-	localBasicFaults[ErrAppConfigError] = fault.NewBasicFault(ErrAppConfigError).SetComponent(ErrApplication).SetResponseType(InternalServer)
-	localBasicFaults[ErrInternalServerError] = fault.NewBasicFault(ErrInternalServerError).SetComponent(ErrApplication).SetResponseType(InternalServer)
-	localBasicFaults[ErrDatabaseInternalError] = fault.NewBasicFault(ErrDatabaseInternalError).SetComponent(ErrRepo).SetResponseType(InternalServer)
-	localBasicFaults[ErrFailedToExtractDataFromRequest] = fault.NewBasicFault(ErrFailedToExtractDataFromRequest).SetComponent(ErrController).SetResponseType(BadRequest)
-	localBasicFaults[ErrGetCallerIdFromHeader] = fault.NewBasicFault(ErrGetCallerIdFromHeader).SetComponent(ErrController).SetResponseType(BadRequest)
-	localBasicFaults[ErrGetUserIdFromGinCtx] = fault.NewBasicFault(ErrGetUserIdFromGinCtx).SetComponent(ErrController).SetResponseType(BadRequest)
-	localBasicFaults[ErrGetUnixTimeFromQueryParam] = fault.NewBasicFault(ErrGetUnixTimeFromQueryParam).SetComponent(ErrController).SetResponseType(BadRequest)
-	localBasicFaults[ErrInvalidRequestBody] = fault.NewBasicFault(ErrInvalidRequestBody).SetComponent(ErrController).SetResponseType(BadRequest)
-	localBasicFaults[ErrGeneratePostRequest] = fault.NewBasicFault(ErrGeneratePostRequest).SetComponent(ErrController).SetResponseType(InternalServer)
-	localBasicFaults[ErrGenerateGetRequest] = fault.NewBasicFault(ErrGenerateGetRequest).SetComponent(ErrController).SetResponseType(InternalServer)
-	localBasicFaults[ErrExecutingRequest] = fault.NewBasicFault(ErrExecutingRequest).SetComponent(ErrController).SetResponseType(InternalServer)
-	localBasicFaults[ErrReadingRespBody] = fault.NewBasicFault(ErrReadingRespBody).SetComponent(ErrController).SetResponseType(InternalServer)
-	localBasicFaults[ErrDecodingResponseBody] = fault.NewBasicFault(ErrDecodingResponseBody).SetComponent(ErrController).SetResponseType(InternalServer)
-	localBasicFaults[ErrUnmarshalResponse] = fault.NewBasicFault(ErrUnmarshalResponse).SetComponent(ErrController).SetResponseType(InternalServer)
-	localBasicFaults[ErrRecordNotFound] = fault.NewBasicFault(ErrRecordNotFound).SetComponent(ErrController).SetResponseType(InternalServer)
-	localBasicFaults[ErrUserNotFound] = fault.NewBasicFault(ErrUserNotFound).SetComponent(ErrController).SetResponseType(BadRequest)
-	localBasicFaults[ErrOrgNotFound] = fault.NewBasicFault(ErrOrgNotFound).SetComponent(ErrController).SetResponseType(BadRequest)
-	localBasicFaults[ErrGetOrgIdFromPathParam] = fault.NewBasicFault(ErrGetOrgIdFromPathParam).SetComponent(ErrController).SetResponseType(BadRequest)
-	localBasicFaults[ErrAuthTokenNotFound] = fault.NewBasicFault(ErrAuthTokenNotFound).SetComponent(ErrController).SetResponseType(Unauthorized)
-	localBasicFaults[ErrInvalidAuthToken] = fault.NewBasicFault(ErrInvalidAuthToken).SetComponent(ErrController).SetResponseType(Unauthorized)
+	for _, def := range builtinFaults {
+		localBasicFaults[def.Code] = def.BasicFault()
+	}
 
 	return localBasicFaults
 }
@@ -142,56 +167,74 @@ var localFaultCache = fault.NewBasicFaultCache(buildBasicFaults())
 
 //TODO Now start defining your Fault constructors as Closures
 
-func ErrInvalidParameterSource(source string) fault.Fault {
-	data := map[string]any{
-		"source": source,
+func _InvalidParameterSourceError(basicFaultCache *fault.BasicFaultsCache) func(string) fault.Fault {
+	return func(source string) fault.Fault {
+		data := map[string]any{
+			"source": source,
+		}
+		return newFault(basicFaultCache, ErrParamSourceInvalid, data, nil)
 	}
-	return fault.NewBasicFault(ErrParamSourceInvalid).
-		SetComponent(ErrLib).SetResponseType(NotFound).
-		ToFault(data, nil)
 }
 
-func ErrInvalidParameter(name string) fault.Fault {
-	data := map[string]any{
-		"name": name,
+var ErrInvalidParameterSource = _InvalidParameterSourceError(&localFaultCache)
+
+func _InvalidParameterError(basicFaultCache *fault.BasicFaultsCache) func(string, error) fault.Fault {
+	return func(name string, cause error) fault.Fault {
+		data := map[string]any{
+			"name": name,
+		}
+		return newFault(basicFaultCache, ErrParamInvalid, data, cause)
 	}
-	return fault.NewBasicFault(ErrParamInvalid).
-		SetComponent(ErrLib).SetResponseType(NotFound).
-		ToFault(data, nil)
 }
 
-func ErrParameterNotFound(name string) fault.Fault {
-	data := map[string]any{
-		"name": name,
+// ErrInvalidParameterWithCause is ErrInvalidParameter keeping the conversion
+// or validation error that made the parameter invalid.
+var ErrInvalidParameterWithCause = _InvalidParameterError(&localFaultCache)
+
+// ErrInvalidParameter raises ErrParamInvalid without a cause, see
+// ErrInvalidParameterWithCause.
+var ErrInvalidParameter = func(name string) fault.Fault {
+	return ErrInvalidParameterWithCause(name, nil)
+}
+
+func _ParameterNotFoundError(basicFaultCache *fault.BasicFaultsCache) func(string) fault.Fault {
+	return func(name string) fault.Fault {
+		data := map[string]any{
+			"name": name,
+		}
+		return newFault(basicFaultCache, ErrParamNotFound, data, nil)
 	}
-	return fault.NewBasicFault(ErrParamNotFound).
-		SetComponent(ErrLib).SetResponseType(NotFound).
-		ToFault(data, nil)
 }
 
-func ErrTypeCastFailed(name string, val string, datatype string, cause error) fault.Fault {
-	data := map[string]any{
-		"name":     name,
-		"val":      val,
-		"datatype": datatype,
+var ErrParameterNotFound = _ParameterNotFoundError(&localFaultCache)
+
+func _TypeCastFailedError(basicFaultCache *fault.BasicFaultsCache) func(string, string, string, error) fault.Fault {
+	return func(name string, val string, datatype string, cause error) fault.Fault {
+		data := map[string]any{
+			"name":     name,
+			"val":      val,
+			"datatype": datatype,
+		}
+		return newFault(basicFaultCache, ErrTypeCast, data, DefaultRedactionPolicy.RedactCause(name, val, cause))
 	}
-	return fault.NewBasicFault(ErrTypeCast).
-		SetComponent(ErrLib).SetResponseType(NotFound).
-		ToFault(data, nil)
 }
 
-func ErrGetValFromGinCtx(name string, cause error) fault.Fault {
-	data := map[string]any{
-		"name": name,
+var ErrTypeCastFailed = _TypeCastFailedError(&localFaultCache)
+
+func _GetValFromGinCtxError(basicFaultCache *fault.BasicFaultsCache) func(string, error) fault.Fault {
+	return func(name string, cause error) fault.Fault {
+		data := map[string]any{
+			"name": name,
+		}
+		return newFault(basicFaultCache, ErrGetValFromGinCtxFailed, data, cause)
 	}
-	return fault.NewBasicFault(ErrGetValFromGinCtxFailed).
-		SetComponent(ErrLib).SetResponseType(NotFound).
-		ToFault(data, nil)
 }
 
+var ErrGetValFromGinCtx = _GetValFromGinCtxError(&localFaultCache)
+
 func _InternalServerError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	var returnFn = func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrInternalServerError).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrInternalServerError, nil, cause)
 	}
 	return returnFn
 }
@@ -200,7 +243,7 @@ var InternalServerError = _InternalServerError(&localFaultCache)
 
 func _ConfigError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	var returnFn = func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrAppConfigError).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrAppConfigError, nil, cause)
 	}
 	return returnFn
 }
@@ -209,7 +252,7 @@ var ConfigError = _ConfigError(&localFaultCache)
 
 func _DBError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	var returnFn = func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrDatabaseInternalError).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrDatabaseInternalError, nil, cause)
 	}
 	return returnFn
 }
@@ -221,7 +264,7 @@ func _RecordNotFoundError(basicFaultCache *fault.BasicFaultsCache) func(string,
 		data := map[string]any{
 			"id": id,
 		}
-		return basicFaultCache.GetBasicFault(ErrRecordNotFound).ToFault(data, cause)
+		return newFault(basicFaultCache, ErrRecordNotFound, data, cause)
 	}
 	return returnFn
 }
@@ -230,7 +273,7 @@ var RecordNotFoundError = _RecordNotFoundError(&localFaultCache)
 
 func _GetRequestDataError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	var returnFn = func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrFailedToExtractDataFromRequest).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrFailedToExtractDataFromRequest, nil, cause)
 	}
 	return returnFn
 }
@@ -239,7 +282,7 @@ var GetRequestDataError = _GetRequestDataError(&localFaultCache)
 
 func _GetUnixTimeFromQueryParamError(basicFaultCache *fault.BasicFaultsCache) func() fault.Fault {
 	var returnFn = func() fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrGetUnixTimeFromQueryParam).ToFault(nil, nil)
+		return newFault(basicFaultCache, ErrGetUnixTimeFromQueryParam, nil, nil)
 	}
 	return returnFn
 }
@@ -251,7 +294,7 @@ func _GetCallerIdError(basicFaultCache *fault.BasicFaultsCache) func(string, err
 		data := map[string]any{
 			"name": name,
 		}
-		return basicFaultCache.GetBasicFault(ErrGetCallerIdFromHeader).ToFault(data, cause)
+		return newFault(basicFaultCache, ErrGetCallerIdFromHeader, data, cause)
 	}
 	return returnFn
 }
@@ -263,7 +306,7 @@ func _GetUserIdError(basicFaultCache *fault.BasicFaultsCache) func(string, error
 		data := map[string]any{
 			"name": id,
 		}
-		return basicFaultCache.GetBasicFault(ErrGetUserIdFromGinCtx).ToFault(data, cause)
+		return newFault(basicFaultCache, ErrGetUserIdFromGinCtx, data, cause)
 	}
 	return returnFn
 }
@@ -272,7 +315,7 @@ var GetUserIdError = _GetUserIdError(&localFaultCache)
 
 func _GeneratePostRequestError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	var returnFn = func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrGeneratePostRequest).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrGeneratePostRequest, nil, cause)
 	}
 	return returnFn
 }
@@ -281,7 +324,7 @@ var GeneratePostRequestError = _GeneratePostRequestError(&localFaultCache)
 
 func _ReadingResponseBodyError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	var returnFn = func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrReadingRespBody).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrReadingRespBody, nil, cause)
 	}
 	return returnFn
 }
@@ -290,7 +333,7 @@ var ReadingResponseBodyError = _ReadingResponseBodyError(&localFaultCache)
 
 func _GenerateGetRequestError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	var returnFn = func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrGenerateGetRequest).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrGenerateGetRequest, nil, cause)
 	}
 	return returnFn
 }
@@ -299,16 +342,28 @@ var GenerateGetRequestError = _GenerateGetRequestError(&localFaultCache)
 
 func _ExecutingRequestError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	var returnFn = func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrExecutingRequest).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrExecutingRequest, nil, cause)
 	}
 	return returnFn
 }
 
 var ExecutingGetRequestError = _ExecutingRequestError(&localFaultCache)
 
+func _ExecutingRequestRetriesError(basicFaultCache *fault.BasicFaultsCache) func(int, error) fault.Fault {
+	var returnFn = func(attempts int, cause error) fault.Fault {
+		data := map[string]any{
+			"attempts": attempts,
+		}
+		return newFault(basicFaultCache, ErrExecutingRequest, data, cause)
+	}
+	return returnFn
+}
+
+var ExecutingRequestRetriesError = _ExecutingRequestRetriesError(&localFaultCache)
+
 func _DecodingResponseBodyError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	var returnFn = func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrDecodingResponseBody).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrDecodingResponseBody, nil, cause)
 	}
 	return returnFn
 }
@@ -317,7 +372,7 @@ var DecodingResponseBodyError = _DecodingResponseBodyError(&localFaultCache)
 
 func _UnmarshalResponseError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	var returnFn = func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrUnmarshalResponse).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrUnmarshalResponse, nil, cause)
 	}
 	return returnFn
 }
@@ -326,7 +381,7 @@ var UnmarshalResponseError = _UnmarshalResponseError(&localFaultCache)
 
 func _InvalidRequestError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	var returnFn = func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrInvalidRequestBody).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrInvalidRequestBody, nil, cause)
 	}
 	return returnFn
 }
@@ -338,7 +393,7 @@ func _OrgNotFoundError(basicFaultCache *fault.BasicFaultsCache) func(error, type
 		data := map[string]any{
 			"org_id": uuid.UUID(orgId),
 		}
-		return basicFaultCache.GetBasicFault(ErrOrgNotFound).ToFault(data, cause)
+		return newFault(basicFaultCache, ErrOrgNotFound, data, cause)
 	}
 	return returnFn
 }
@@ -350,7 +405,7 @@ func _UserNotFoundError(basicFaultCache *fault.BasicFaultsCache) func(error, typ
 		data := map[string]any{
 			"user_id": uuid.UUID(userId),
 		}
-		return basicFaultCache.GetBasicFault(ErrUserNotFound).ToFault(data, cause)
+		return newFault(basicFaultCache, ErrUserNotFound, data, cause)
 	}
 	return returnFn
 }
@@ -359,7 +414,7 @@ var UserNotFoundError = _UserNotFoundError(&localFaultCache)
 
 func _GetOrgIdFromParamError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	return func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrGetOrgIdFromPathParam).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrGetOrgIdFromPathParam, nil, cause)
 	}
 }
 
@@ -367,7 +422,7 @@ var GetOrgIdFromParamError = _GetOrgIdFromParamError(&localFaultCache)
 
 func _AuthTokenNotFoundError(basicFaultCache *fault.BasicFaultsCache) func() fault.Fault {
 	return func() fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrAuthTokenNotFound).ToFault(nil, nil)
+		return newFault(basicFaultCache, ErrAuthTokenNotFound, nil, nil)
 	}
 }
 
@@ -375,8 +430,41 @@ var AuthTokenNotFoundError = _AuthTokenNotFoundError(&localFaultCache)
 
 func _AuthTokenInvalidError(basicFaultCache *fault.BasicFaultsCache) func(error) fault.Fault {
 	return func(cause error) fault.Fault {
-		return basicFaultCache.GetBasicFault(ErrInvalidAuthToken).ToFault(nil, cause)
+		return newFault(basicFaultCache, ErrInvalidAuthToken, nil, cause)
 	}
 }
 
 var AuthTokenInvalidError = _AuthTokenInvalidError(&localFaultCache)
+
+func _CircuitOpenError(basicFaultCache *fault.BasicFaultsCache) func(string) fault.Fault {
+	return func(host string) fault.Fault {
+		data := map[string]any{
+			"host": host,
+		}
+		return newFault(basicFaultCache, ErrCircuitOpen, data, nil)
+	}
+}
+
+var CircuitOpenError = _CircuitOpenError(&localFaultCache)
+
+func _RequestTimeoutError(basicFaultCache *fault.BasicFaultsCache) func(string, error) fault.Fault {
+	return func(route string, cause error) fault.Fault {
+		data := map[string]any{
+			"route": route,
+		}
+		return newFault(basicFaultCache, ErrRequestTimeout, data, cause)
+	}
+}
+
+var RequestTimeoutError = _RequestTimeoutError(&localFaultCache)
+
+func _RateLimitExceededError(basicFaultCache *fault.BasicFaultsCache) func(int) fault.Fault {
+	return func(retryAfter int) fault.Fault {
+		data := map[string]any{
+			"retry_after": retryAfter,
+		}
+		return newFault(basicFaultCache, ErrRateLimitExceeded, data, nil)
+	}
+}
+
+var RateLimitExceededError = _RateLimitExceededError(&localFaultCache)
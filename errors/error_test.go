@@ -0,0 +1,38 @@
+package errors
+
+import (
+	stderrors "errors"
+	"strconv"
+	"testing"
+)
+
+func TestInvalidParameterKeepsItsCause(t *testing.T) {
+	_, cause := strconv.Atoi("ten")
+	f := ErrInvalidParameterWithCause("limit", cause)
+
+	var numErr *strconv.NumError
+	if !stderrors.As(f, &numErr) || numErr.Func != "Atoi" {
+		t.Errorf("cause not found by errors.As: %v", f.Cause())
+	}
+	if !stderrors.Is(f, cause) {
+		t.Error("cause not found by errors.Is")
+	}
+	if ErrInvalidParameter("limit").Cause() != nil {
+		t.Error("ErrInvalidParameter set a cause")
+	}
+}
+
+func TestIsMatchesCodesInTheCauseChain(t *testing.T) {
+	wrapped := InternalServerError(ErrInvalidParameter("limit"))
+
+	if !stderrors.Is(wrapped, ErrInvalidParameter("")) {
+		t.Error("wrapped code not matched")
+	}
+	if stderrors.Is(wrapped, ErrParameterNotFound("")) {
+		t.Error("matched a code that is not in the chain")
+	}
+	var defined *DefinedFault
+	if !stderrors.As(wrapped, &defined) || defined.Code() != ErrInternalServerError {
+		t.Errorf("errors.As found %v", defined)
+	}
+}
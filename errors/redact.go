@@ -0,0 +1,144 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const Redacted = "[REDACTED]"
+
+// RedactionPolicy decides which fault data values must never reach a
+// response or a log line. A value is redacted when
+//   - its data key is a sensitive name, e.g. {"authorization": "..."}
+//   - it is the "val" of a parameter whose "name" is sensitive, as in
+//     ErrTypeCastFailed
+//   - it is a string matching one of the patterns
+//
+// Names are compared case-insensitively.
+type RedactionPolicy struct {
+	mu       sync.RWMutex
+	names    map[string]struct{}
+	patterns []*regexp.Regexp
+}
+
+func NewRedactionPolicy() *RedactionPolicy {
+	return &RedactionPolicy{names: map[string]struct{}{}}
+}
+
+// RedactParameters marks path/query parameter names as sensitive.
+func (p *RedactionPolicy) RedactParameters(names ...string) *RedactionPolicy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, name := range names {
+		p.names[strings.ToLower(name)] = struct{}{}
+	}
+	return p
+}
+
+// RedactHeaders marks HTTP header names as sensitive.
+func (p *RedactionPolicy) RedactHeaders(headers ...string) *RedactionPolicy {
+	return p.RedactParameters(headers...)
+}
+
+// RedactPattern redacts every string value matching re, whatever its key.
+func (p *RedactionPolicy) RedactPattern(re *regexp.Regexp) *RedactionPolicy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.patterns = append(p.patterns, re)
+	return p
+}
+
+func (p *RedactionPolicy) isSensitiveName(name string) bool {
+	_, ok := p.names[strings.ToLower(name)]
+	return ok
+}
+
+func (p *RedactionPolicy) matchesPattern(val string) bool {
+	for _, re := range p.patterns {
+		if re.MatchString(val) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns a copy of data with the sensitive values replaced by
+// Redacted. Nested maps are redacted too.
+func (p *RedactionPolicy) Redact(data map[string]any) map[string]any {
+	if data == nil {
+		return nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.redact(data)
+}
+
+// RedactCause returns the cause of a fault about parameter name with value
+// val taken out when the name is sensitive or val matches a pattern:
+// conversion errors such as *strconv.NumError quote the value they failed
+// on. The error kind, e.g. strconv.ErrSyntax, is kept.
+func (p *RedactionPolicy) RedactCause(name string, val string, cause error) error {
+	if cause == nil {
+		return nil
+	}
+	p.mu.RLock()
+	sensitive := p.isSensitiveName(name) || p.matchesPattern(val)
+	p.mu.RUnlock()
+	if !sensitive {
+		return cause
+	}
+
+	var numErr *strconv.NumError
+	if stderrors.As(cause, &numErr) {
+		return &strconv.NumError{Func: numErr.Func, Num: Redacted, Err: numErr.Err}
+	}
+	return fmt.Errorf("invalid value %s for %s", Redacted, name)
+}
+
+func (p *RedactionPolicy) redact(data map[string]any) map[string]any {
+	sensitiveParam := false
+	if name, ok := data["name"].(string); ok {
+		sensitiveParam = p.isSensitiveName(name)
+	}
+
+	redacted := make(map[string]any, len(data))
+	for key, val := range data {
+		switch {
+		case p.isSensitiveName(key), sensitiveParam && key == "val":
+			redacted[key] = Redacted
+		default:
+			redacted[key] = p.redactValue(val)
+		}
+	}
+	return redacted
+}
+
+func (p *RedactionPolicy) redactValue(val any) any {
+	switch v := val.(type) {
+	case map[string]any:
+		return p.redact(v)
+	case string:
+		if p.matchesPattern(v) {
+			return Redacted
+		}
+	case fmt.Stringer:
+		if p.matchesPattern(v.String()) {
+			return Redacted
+		}
+	}
+	return val
+}
+
+// DefaultRedactionPolicy is applied to the data of every fault built by this
+// package. It covers credentials carried in headers, cookies and common
+// query parameters, bearer tokens and JWTs; applications add their own names
+// and patterns to it.
+var DefaultRedactionPolicy = NewRedactionPolicy().
+	RedactHeaders("Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token", "X-Csrf-Token").
+	RedactParameters("token", "access_token", "refresh_token", "id_token", "api_key", "apikey", "password", "secret", "client_secret").
+	RedactPattern(regexp.MustCompile(`(?i)^\s*(bearer|basic)\s+\S+`)).
+	RedactPattern(regexp.MustCompile(`^eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*$`))
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	stderrors "errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	policy := NewRedactionPolicy().
+		RedactHeaders("Authorization").
+		RedactParameters("pin").
+		RedactPattern(regexp.MustCompile(`^sk_`))
+
+	redacted := policy.Redact(map[string]any{
+		"authorization": "Bearer abc",
+		"name":          "PIN",
+		"val":           "1234",
+		"key":           "sk_live_123",
+		"nested":        map[string]any{"Authorization": "x", "user": "jane"},
+	})
+
+	for key, want := range map[string]any{"authorization": Redacted, "name": "PIN", "val": Redacted, "key": Redacted} {
+		if redacted[key] != want {
+			t.Errorf("%s = %v, want %v", key, redacted[key], want)
+		}
+	}
+	nested := redacted["nested"].(map[string]any)
+	if nested["Authorization"] != Redacted || nested["user"] != "jane" {
+		t.Errorf("nested = %v", nested)
+	}
+}
+
+func TestRedactCause(t *testing.T) {
+	policy := NewRedactionPolicy().RedactParameters("password").RedactPattern(regexp.MustCompile(`^sk_`))
+
+	tests := []struct {
+		name     string
+		param    string
+		val      string
+		redacted bool
+	}{
+		{"sensitive name", "password", "hunter2", true},
+		{"matching value", "limit", "sk_live_123", true},
+		{"neither", "limit", "ten", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, cause := strconv.ParseUint(tt.val, 10, 64)
+			got := policy.RedactCause(tt.param, tt.val, cause)
+			if !tt.redacted {
+				if got != cause {
+					t.Errorf("cause replaced: %v", got)
+				}
+				return
+			}
+			if strings.Contains(got.Error(), tt.val) {
+				t.Errorf("cause still quotes the value: %v", got)
+			}
+			if !stderrors.Is(got, strconv.ErrSyntax) {
+				t.Errorf("error kind lost: %v", got)
+			}
+		})
+	}
+	if policy.RedactCause("password", "hunter2", nil) != nil {
+		t.Error("nil cause replaced")
+	}
+}
+
+func TestTypeCastFaultHidesSensitiveValues(t *testing.T) {
+	for param, val := range map[string]string{"token": "s3cr3t", "limit": "Bearer abc.def"} {
+		_, cause := strconv.ParseBool(val)
+		f := ErrTypeCastFailed(param, val, "bool", cause)
+
+		for err := error(f); err != nil; err = stderrors.Unwrap(err) {
+			if strings.Contains(err.Error(), val) {
+				t.Fatalf("%s: value leaked through %T: %v", param, err, err)
+			}
+		}
+		if f.(*DefinedFault).Data()["val"] != Redacted {
+			t.Errorf("%s: data = %v", param, f.(*DefinedFault).Data())
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package errors
+
+import (
+	"bytes"
+	stderrors "errors"
+	"fmt"
+	"sort"
+	"sync"
+	"text/template"
+
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+)
+
+const DefaultLocale = "en"
+
+// FaultDefinition describes an error code: who raises it, how it is rendered
+// to clients and the message template filled from the fault data, e.g.
+// "user {{.user_id}} not found".
+type FaultDefinition struct {
+	Code         fault.ErrorCode
+	Component    fault.ErrComponent
+	ResponseType fault.ResponseErrType
+	// HttpStatus overrides the status derived from ResponseType when set.
+	HttpStatus int
+	// Message is the template in DefaultLocale.
+	Message string
+	// Translations holds the template in other locales, keyed by locale.
+	Translations map[string]string
+}
+
+// Messages returns the message template of every locale, DefaultLocale
+// included.
+func (d FaultDefinition) Messages() map[string]string {
+	messages := make(map[string]string, len(d.Translations)+1)
+	for locale, message := range d.Translations {
+		messages[locale] = message
+	}
+	messages[DefaultLocale] = d.Message
+	return messages
+}
+
+func (d FaultDefinition) BasicFault() fault.BasicFault {
+	return fault.NewBasicFault(d.Code).SetComponent(d.Component).SetResponseType(d.ResponseType)
+}
+
+// Constructor builds a fault of a registered code from its data and cause.
+// The data goes through DefaultRedactionPolicy first.
+type Constructor func(data map[string]any, cause error) fault.Fault
+
+// DefinedFault is the fault.Fault built by a registry Constructor. It keeps
+// the data and definition around so the fault can be rendered.
+type DefinedFault struct {
+	fault.Fault
+	definition FaultDefinition
+	message    *template.Template
+	data       map[string]any
+}
+
+func (f *DefinedFault) Definition() FaultDefinition {
+	return f.definition
+}
+
+func (f *DefinedFault) Data() map[string]any {
+	return f.data
+}
+
+// Unwrap exposes the cause to errors.Is and errors.As.
+func (f *DefinedFault) Unwrap() error {
+	return f.Fault.Cause()
+}
+
+// Is matches any fault with the same code, so errors.Is(err, target) finds a
+// code anywhere in the cause chain.
+func (f *DefinedFault) Is(target error) bool {
+	t, ok := target.(fault.Fault)
+	return ok && t.Code() == f.Code()
+}
+
+// Message renders the definition's message template with the fault data.
+func (f *DefinedFault) Message() string {
+	if f.message == nil {
+		return f.definition.Message
+	}
+	return execute(f.message, f.data, f.definition.Message)
+}
+
+// LocalizedMessage renders the message in locale, falling back to
+// DefaultLocale when there is no translation.
+func (f *DefinedFault) LocalizedMessage(locale string) string {
+	translation, ok := f.definition.Translations[locale]
+	if !ok {
+		return f.Message()
+	}
+	tmpl, err := parseMessage(f.definition.Code, translation)
+	if err != nil {
+		return translation
+	}
+	return execute(tmpl, f.data, translation)
+}
+
+func parseMessage(code fault.ErrorCode, message string) (*template.Template, error) {
+	return template.New(code.String()).Option("missingkey=zero").Parse(message)
+}
+
+func execute(tmpl *template.Template, data map[string]any, fallback string) string {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return fallback
+	}
+	return buf.String()
+}
+
+type DuplicateCodeError struct {
+	Code fault.ErrorCode
+}
+
+func (e DuplicateCodeError) Error() string {
+	return fmt.Sprintf("error code %s is already registered", e.Code)
+}
+
+// Registry holds the definition of every error code an application can
+// raise. Codes are unique across the registry.
+type Registry struct {
+	mu          sync.RWMutex
+	definitions map[fault.ErrorCode]FaultDefinition
+	templates   map[fault.ErrorCode]*template.Template
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		definitions: map[fault.ErrorCode]FaultDefinition{},
+		templates:   map[fault.ErrorCode]*template.Template{},
+	}
+}
+
+// Register adds a definition and returns its constructor. It fails on a
+// code that is already registered or a message template, in any locale,
+// that does not parse.
+func (r *Registry) Register(def FaultDefinition) (Constructor, error) {
+	tmpl, err := parseMessage(def.Code, def.Message)
+	if err != nil {
+		return nil, fmt.Errorf("error code %s: invalid message template: %w", def.Code, err)
+	}
+	for locale, translation := range def.Translations {
+		if _, err := parseMessage(def.Code, translation); err != nil {
+			return nil, fmt.Errorf("error code %s: invalid %s message template: %w", def.Code, locale, err)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.definitions[def.Code]; exists {
+		return nil, DuplicateCodeError{Code: def.Code}
+	}
+	r.definitions[def.Code] = def
+	r.templates[def.Code] = tmpl
+
+	basicFault := def.BasicFault()
+	return func(data map[string]any, cause error) fault.Fault {
+		data = DefaultRedactionPolicy.Redact(data)
+		return &DefinedFault{
+			Fault:      basicFault.ToFault(data, cause),
+			definition: def,
+			message:    tmpl,
+			data:       data,
+		}
+	}, nil
+}
+
+// MustRegister is Register for package level variables: a duplicate code
+// panics at startup instead of surfacing at request time.
+func (r *Registry) MustRegister(def FaultDefinition) Constructor {
+	constructor, err := r.Register(def)
+	if err != nil {
+		panic(err)
+	}
+	return constructor
+}
+
+func (r *Registry) Lookup(code fault.ErrorCode) (FaultDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	def, ok := r.definitions[code]
+	return def, ok
+}
+
+// Definitions returns every registered definition sorted by code.
+func (r *Registry) Definitions() []FaultDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	defs := make([]FaultDefinition, 0, len(r.definitions))
+	for _, def := range r.definitions {
+		defs = append(defs, def)
+	}
+	sort.Slice(defs, func(i, j int) bool {
+		return defs[i].Code < defs[j].Code
+	})
+	return defs
+}
+
+func (r *Registry) lookupTemplate(code fault.ErrorCode) (FaultDefinition, *template.Template) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	def, ok := r.definitions[code]
+	if !ok {
+		def = FaultDefinition{Code: code}
+	}
+	tmpl, ok := r.templates[code]
+	if !ok {
+		tmpl = template.Must(template.New(code.String()).Parse(""))
+	}
+	return def, tmpl
+}
+
+// newFault builds a library fault from the cache and attaches its registered
+// definition, keeping the data renderable and the cause unwrappable. The data
+// is redacted before it is stored anywhere.
+func newFault(basicFaultCache *fault.BasicFaultsCache, code fault.ErrorCode, data map[string]any, cause error) fault.Fault {
+	data = DefaultRedactionPolicy.Redact(data)
+	def, tmpl := DefaultRegistry.lookupTemplate(code)
+	return &DefinedFault{
+		Fault:      basicFaultCache.GetBasicFault(code).ToFault(data, cause),
+		definition: def,
+		message:    tmpl,
+		data:       data,
+	}
+}
+
+// DownstreamFault rebuilds a fault raised by another service from the code,
+// data and message of its error envelope. A code registered here keeps its
+// registered definition; an unknown one renders with the downstream status
+// and message.
+func DownstreamFault(code fault.ErrorCode, data map[string]any, status int, message string, cause error) fault.Fault {
+	data = DefaultRedactionPolicy.Redact(data)
+	def, tmpl := DefaultRegistry.lookupTemplate(code)
+	if _, known := DefaultRegistry.Lookup(code); !known {
+		def = FaultDefinition{
+			Code:         code,
+			Component:    ErrService,
+			ResponseType: ResponseTypeForStatus(status),
+			HttpStatus:   status,
+			Message:      message,
+		}
+		tmpl = nil
+	}
+	return &DefinedFault{
+		Fault:      def.BasicFault().ToFault(data, cause),
+		definition: def,
+		message:    tmpl,
+		data:       data,
+	}
+}
+
+// HasCode reports whether err or any error in its cause chain is a fault
+// with the given code.
+func HasCode(err error, code fault.ErrorCode) bool {
+	for err != nil {
+		if f, ok := err.(fault.Fault); ok {
+			if f.Code() == code {
+				return true
+			}
+			err = f.Cause()
+			continue
+		}
+		err = stderrors.Unwrap(err)
+	}
+	return false
+}
+
+// DefaultRegistry holds the library's own codes; applications register
+// theirs alongside through Register and MustRegister.
+var DefaultRegistry = newDefaultRegistry()
+
+func newDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, def := range builtinFaults {
+		r.MustRegister(def)
+	}
+	return r
+}
+
+func Register(def FaultDefinition) (Constructor, error) {
+	return DefaultRegistry.Register(def)
+}
+
+func MustRegister(def FaultDefinition) Constructor {
+	return DefaultRegistry.MustRegister(def)
+}
+
+func Lookup(code fault.ErrorCode) (FaultDefinition, bool) {
+	return DefaultRegistry.Lookup(code)
+}
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PrathamSkilltelligent/pmgingo/types"
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+)
+
+// builtinConstructors builds a sample fault through each library
+// constructor, keyed by the code it is expected to raise.
+var builtinConstructors = map[fault.ErrorCode]func() fault.Fault{
+	ErrParamNotFound:                  func() fault.Fault { return ErrParameterNotFound("") },
+	ErrParamInvalid:                   func() fault.Fault { return ErrInvalidParameter("") },
+	ErrParamSourceInvalid:             func() fault.Fault { return ErrInvalidParameterSource("") },
+	ErrTypeCast:                       func() fault.Fault { return ErrTypeCastFailed("", "", "", nil) },
+	ErrGetValFromGinCtxFailed:         func() fault.Fault { return ErrGetValFromGinCtx("", nil) },
+	ErrAppConfigError:                 func() fault.Fault { return ConfigError(nil) },
+	ErrInternalServerError:            func() fault.Fault { return InternalServerError(nil) },
+	ErrDatabaseInternalError:          func() fault.Fault { return DBError(nil) },
+	ErrFailedToExtractDataFromRequest: func() fault.Fault { return GetRequestDataError(nil) },
+	ErrGetCallerIdFromHeader:          func() fault.Fault { return GetCallerIdError("", nil) },
+	ErrGetUserIdFromGinCtx:            func() fault.Fault { return GetUserIdError("", nil) },
+	ErrGetUnixTimeFromQueryParam:      func() fault.Fault { return GetUnixTimeFromQueryParamError() },
+	ErrGetOrgIdFromPathParam:          func() fault.Fault { return GetOrgIdFromParamError(nil) },
+	ErrInvalidRequestBody:             func() fault.Fault { return InvalidRequestError(nil) },
+	ErrGeneratePostRequest:            func() fault.Fault { return GeneratePostRequestError(nil) },
+	ErrGenerateGetRequest:             func() fault.Fault { return GenerateGetRequestError(nil) },
+	ErrExecutingRequest:               func() fault.Fault { return ExecutingGetRequestError(nil) },
+	ErrReadingRespBody:                func() fault.Fault { return ReadingResponseBodyError(nil) },
+	ErrDecodingResponseBody:           func() fault.Fault { return DecodingResponseBodyError(nil) },
+	ErrUnmarshalResponse:              func() fault.Fault { return UnmarshalResponseError(nil) },
+	ErrAuthTokenNotFound:              func() fault.Fault { return AuthTokenNotFoundError() },
+	ErrInvalidAuthToken:               func() fault.Fault { return AuthTokenInvalidError(nil) },
+	ErrCircuitOpen:                    func() fault.Fault { return CircuitOpenError("") },
+	ErrRequestTimeout:                 func() fault.Fault { return RequestTimeoutError("", nil) },
+	ErrRateLimitExceeded:              func() fault.Fault { return RateLimitExceededError(0) },
+	ErrRecordNotFound:                 func() fault.Fault { return RecordNotFoundError("", nil) },
+	ErrUserNotFound:                   func() fault.Fault { return UserNotFoundError(nil, types.UserId{}) },
+	ErrOrgNotFound:                    func() fault.Fault { return OrgNotFoundError(nil, types.OrgId{}) },
+}
+
+// CheckConstructors verifies that every library code registered in the
+// registry has a constructor and that the constructor raises that code with
+// the registered response type. It is the startup self-check: server.New
+// runs it against DefaultRegistry, and applications building their own
+// engine should call it once at startup. The library's tests hold the
+// constructors to an independent table of codes too.
+func CheckConstructors(registry *Registry) error {
+	var mismatches []string
+	for _, def := range builtinFaults {
+		registered, ok := registry.Lookup(def.Code)
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s: not registered", def.Code))
+			continue
+		}
+		constructor, ok := builtinConstructors[def.Code]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s: no constructor", def.Code))
+			continue
+		}
+		f := constructor()
+		if f.Code() != registered.Code {
+			mismatches = append(mismatches, fmt.Sprintf("%s: constructor raises %s", def.Code, f.Code()))
+		}
+		if f.ResponseErrType() != registered.ResponseType {
+			mismatches = append(mismatches, fmt.Sprintf("%s: constructor responds %s, registry says %s",
+				def.Code, f.ResponseErrType(), registered.ResponseType))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("fault constructors disagree with the registry: %s", strings.Join(mismatches, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+)
+
+// libraryFaults is the documented contract of the library codes, written out
+// independently of builtinFaults so that a change to either shows up here.
+var libraryFaults = []struct {
+	code         fault.ErrorCode
+	responseType fault.ResponseErrType
+	status       int
+}{
+	{ErrParamNotFound, BadRequest, http.StatusBadRequest},
+	{ErrParamInvalid, BadRequest, http.StatusBadRequest},
+	{ErrParamSourceInvalid, BadRequest, http.StatusBadRequest},
+	{ErrTypeCast, BadRequest, http.StatusBadRequest},
+	{ErrGetValFromGinCtxFailed, InternalServer, http.StatusInternalServerError},
+	{ErrAppConfigError, InternalServer, http.StatusInternalServerError},
+	{ErrInternalServerError, InternalServer, http.StatusInternalServerError},
+	{ErrDatabaseInternalError, InternalServer, http.StatusInternalServerError},
+	{ErrFailedToExtractDataFromRequest, BadRequest, http.StatusBadRequest},
+	{ErrGetCallerIdFromHeader, BadRequest, http.StatusBadRequest},
+	{ErrGetUserIdFromGinCtx, BadRequest, http.StatusBadRequest},
+	{ErrGetUnixTimeFromQueryParam, BadRequest, http.StatusBadRequest},
+	{ErrInvalidRequestBody, BadRequest, http.StatusBadRequest},
+	{ErrGeneratePostRequest, InternalServer, http.StatusInternalServerError},
+	{ErrGenerateGetRequest, InternalServer, http.StatusInternalServerError},
+	{ErrExecutingRequest, InternalServer, http.StatusInternalServerError},
+	{ErrReadingRespBody, InternalServer, http.StatusInternalServerError},
+	{ErrDecodingResponseBody, InternalServer, http.StatusInternalServerError},
+	{ErrUnmarshalResponse, InternalServer, http.StatusInternalServerError},
+	{ErrRecordNotFound, InternalServer, http.StatusInternalServerError},
+	{ErrUserNotFound, BadRequest, http.StatusBadRequest},
+	{ErrOrgNotFound, BadRequest, http.StatusBadRequest},
+	{ErrGetOrgIdFromPathParam, BadRequest, http.StatusBadRequest},
+	{ErrAuthTokenNotFound, Unauthorized, http.StatusUnauthorized},
+	{ErrInvalidAuthToken, Unauthorized, http.StatusUnauthorized},
+	{ErrCircuitOpen, ServiceUnavailable, http.StatusServiceUnavailable},
+	{ErrRequestTimeout, GatewayTimeout, http.StatusGatewayTimeout},
+	{ErrRateLimitExceeded, TooManyRequests, http.StatusTooManyRequests},
+}
+
+func TestConstructorsMatchLibraryFaults(t *testing.T) {
+	if len(builtinConstructors) != len(libraryFaults) {
+		t.Errorf("%d constructors for %d library codes", len(builtinConstructors), len(libraryFaults))
+	}
+	for _, want := range libraryFaults {
+		t.Run(want.code.String(), func(t *testing.T) {
+			constructor, ok := builtinConstructors[want.code]
+			if !ok {
+				t.Fatal("no constructor")
+			}
+			f := constructor()
+			if f.Code() != want.code {
+				t.Errorf("code = %s", f.Code())
+			}
+			if f.ResponseErrType() != want.responseType {
+				t.Errorf("response type = %s, want %s", f.ResponseErrType(), want.responseType)
+			}
+			if status := FaultStatusCode(f); status != want.status {
+				t.Errorf("status = %d, want %d", status, want.status)
+			}
+		})
+	}
+}
+
+func TestCheckConstructors(t *testing.T) {
+	if err := CheckConstructors(DefaultRegistry); err != nil {
+		t.Fatal(err)
+	}
+
+	registry := NewRegistry()
+	for _, def := range builtinFaults {
+		if def.Code == ErrRecordNotFound {
+			def.ResponseType = BadRequest
+		}
+		registry.MustRegister(def)
+	}
+	if err := CheckConstructors(registry); err == nil {
+		t.Fatal("expected a mismatch for a code registered with another response type")
+	}
+}
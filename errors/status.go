@@ -0,0 +1,121 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+)
+
+// responseStatuses maps response types to HTTP statuses and back. types
+// holds the canonical response type of each status: the generic built-in one
+// when several built-ins share a status, otherwise the first type registered
+// for it.
+var responseStatuses = struct {
+	mu       sync.RWMutex
+	statuses map[fault.ResponseErrType]int
+	types    map[int]fault.ResponseErrType
+}{
+	statuses: map[fault.ResponseErrType]int{
+		BadRequest:           http.StatusBadRequest,
+		Unauthorized:         http.StatusUnauthorized,
+		Forbidden:            http.StatusForbidden,
+		NotFound:             http.StatusNotFound,
+		MethodNotAllowed:     http.StatusMethodNotAllowed,
+		RequestTimeout:       http.StatusRequestTimeout,
+		AlreadyExists:        http.StatusConflict,
+		Conflict:             http.StatusConflict,
+		PreconditionFailed:   http.StatusPreconditionFailed,
+		PayloadTooLarge:      http.StatusRequestEntityTooLarge,
+		UnsupportedMediaType: http.StatusUnsupportedMediaType,
+		UnprocessableEntity:  http.StatusUnprocessableEntity,
+		TooManyRequests:      http.StatusTooManyRequests,
+		InternalServer:       http.StatusInternalServerError,
+		BadGateway:           http.StatusBadGateway,
+		ServiceUnavailable:   http.StatusServiceUnavailable,
+		GatewayTimeout:       http.StatusGatewayTimeout,
+	},
+	types: map[int]fault.ResponseErrType{
+		http.StatusBadRequest:            BadRequest,
+		http.StatusUnauthorized:          Unauthorized,
+		http.StatusForbidden:             Forbidden,
+		http.StatusNotFound:              NotFound,
+		http.StatusMethodNotAllowed:      MethodNotAllowed,
+		http.StatusRequestTimeout:        RequestTimeout,
+		http.StatusConflict:              Conflict,
+		http.StatusPreconditionFailed:    PreconditionFailed,
+		http.StatusRequestEntityTooLarge: PayloadTooLarge,
+		http.StatusUnsupportedMediaType:  UnsupportedMediaType,
+		http.StatusUnprocessableEntity:   UnprocessableEntity,
+		http.StatusTooManyRequests:       TooManyRequests,
+		http.StatusInternalServerError:   InternalServer,
+		http.StatusBadGateway:            BadGateway,
+		http.StatusServiceUnavailable:    ServiceUnavailable,
+		http.StatusGatewayTimeout:        GatewayTimeout,
+	},
+}
+
+// RegisterResponseType maps an application specific response type, e.g.
+// "QuotaExceeded", to the HTTP status it renders as. A type can only be
+// mapped once.
+func RegisterResponseType(responseType fault.ResponseErrType, status int) error {
+	if status < 100 || status > 599 {
+		return fmt.Errorf("response type %s: invalid http status %d", responseType, status)
+	}
+
+	responseStatuses.mu.Lock()
+	defer responseStatuses.mu.Unlock()
+	if existing, ok := responseStatuses.statuses[responseType]; ok {
+		return fmt.Errorf("response type %s is already mapped to http status %d", responseType, existing)
+	}
+	responseStatuses.statuses[responseType] = status
+	if _, ok := responseStatuses.types[status]; !ok {
+		responseStatuses.types[status] = responseType
+	}
+	return nil
+}
+
+// MustRegisterResponseType is RegisterResponseType for package level
+// variables and init functions.
+func MustRegisterResponseType(responseType fault.ResponseErrType, status int) fault.ResponseErrType {
+	if err := RegisterResponseType(responseType, status); err != nil {
+		panic(err)
+	}
+	return responseType
+}
+
+// StatusCode returns the HTTP status of a response type, 500 for unknown
+// types.
+func StatusCode(responseType fault.ResponseErrType) int {
+	responseStatuses.mu.RLock()
+	defer responseStatuses.mu.RUnlock()
+	if status, ok := responseStatuses.statuses[responseType]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// FaultStatusCode returns the HTTP status a fault renders as: the HttpStatus
+// of its definition when set, otherwise the status of its response type.
+func FaultStatusCode(f fault.Fault) int {
+	if defined, ok := f.(*DefinedFault); ok && defined.definition.HttpStatus != 0 {
+		return defined.definition.HttpStatus
+	}
+	if def, ok := Lookup(f.Code()); ok && def.HttpStatus != 0 {
+		return def.HttpStatus
+	}
+	return StatusCode(f.ResponseErrType())
+}
+
+// ResponseTypeForStatus returns the response type rendering as status: the
+// generic built-in one when several built-ins do (Conflict for 409), else the
+// first one registered for it, and InternalServer when none does.
+func ResponseTypeForStatus(status int) fault.ResponseErrType {
+	responseStatuses.mu.RLock()
+	defer responseStatuses.mu.RUnlock()
+	if responseType, ok := responseStatuses.types[status]; ok {
+		return responseType
+	}
+	return InternalServer
+}
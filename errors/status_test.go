@@ -0,0 +1,96 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+)
+
+func TestResponseTypeForStatusIsCanonical(t *testing.T) {
+	for status, want := range map[int]fault.ResponseErrType{
+		http.StatusBadRequest:          BadRequest,
+		http.StatusNotFound:            NotFound,
+		http.StatusConflict:            Conflict,
+		http.StatusTooManyRequests:     TooManyRequests,
+		http.StatusInternalServerError: InternalServer,
+		http.StatusGatewayTimeout:      GatewayTimeout,
+		http.StatusTeapot:              InternalServer,
+	} {
+		// AlreadyExists shares 409 with Conflict: the answer must not depend
+		// on map iteration order.
+		for i := 0; i < 20; i++ {
+			if got := ResponseTypeForStatus(status); got != want {
+				t.Fatalf("ResponseTypeForStatus(%d) = %s, want %s", status, got, want)
+			}
+		}
+	}
+}
+
+func TestRegisteredResponseTypes(t *testing.T) {
+	const quotaExceeded fault.ResponseErrType = "QuotaExceeded"
+	const paymentRequired fault.ResponseErrType = "PaymentRequired"
+	t.Cleanup(func() {
+		unregisterResponseType(quotaExceeded)
+		unregisterResponseType(paymentRequired)
+	})
+	if err := RegisterResponseType(quotaExceeded, http.StatusPaymentRequired); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterResponseType(paymentRequired, http.StatusPaymentRequired); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := StatusCode(quotaExceeded); got != http.StatusPaymentRequired {
+		t.Errorf("StatusCode = %d", got)
+	}
+	if got := ResponseTypeForStatus(http.StatusPaymentRequired); got != quotaExceeded {
+		t.Errorf("ResponseTypeForStatus = %s, want the first registered", got)
+	}
+	if err := RegisterResponseType(quotaExceeded, http.StatusForbidden); err == nil {
+		t.Error("response type mapped twice")
+	}
+	if err := RegisterResponseType("Bogus", 42); err == nil {
+		t.Error("invalid status accepted")
+	}
+	if got := ResponseTypeForStatus(http.StatusConflict); got != Conflict {
+		t.Errorf("a registration changed the built-in type of 409 to %s", got)
+	}
+}
+
+// unregisterResponseType undoes RegisterResponseType so that tests can run
+// more than once in a process.
+func unregisterResponseType(responseType fault.ResponseErrType) {
+	responseStatuses.mu.Lock()
+	defer responseStatuses.mu.Unlock()
+	status, ok := responseStatuses.statuses[responseType]
+	if !ok {
+		return
+	}
+	delete(responseStatuses.statuses, responseType)
+	if responseStatuses.types[status] == responseType {
+		delete(responseStatuses.types, status)
+	}
+}
+
+func TestDownstreamFaultStatus(t *testing.T) {
+	unknown := DownstreamFault("EXT0000000001", map[string]any{"token": "secret"}, http.StatusTeapot, "short and stout", nil)
+	if got := FaultStatusCode(unknown); got != http.StatusTeapot {
+		t.Errorf("status of an unknown code = %d", got)
+	}
+	defined, ok := unknown.(*DefinedFault)
+	if !ok {
+		t.Fatalf("%T", unknown)
+	}
+	if defined.Message() != "short and stout" {
+		t.Errorf("message = %q", defined.Message())
+	}
+	if defined.Data()["token"] != Redacted {
+		t.Errorf("data not redacted: %v", defined.Data())
+	}
+
+	known := DownstreamFault(ErrCircuitOpen, nil, http.StatusBadGateway, "", nil)
+	if got := FaultStatusCode(known); got != http.StatusServiceUnavailable {
+		t.Errorf("status of a registered code = %d", got)
+	}
+}
@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+)
+
+// TypedConstructor builds a fault of a registered code from a typed data
+// struct instead of a map, so that a misspelt key fails to compile.
+type TypedConstructor[D any] func(data D, cause error) fault.Fault
+
+// RegisterTyped is Registry.Register for faults whose data is a struct D.
+// The struct is converted to the data map through its JSON encoding, so the
+// json tags name the keys the message template refers to, e.g.
+//
+//	type userNotFound struct {
+//		UserId string `json:"user_id"`
+//	}
+//
+// with the message "user {{.user_id}} not found". It fails when D is not a
+// struct, a pointer to one or a map with string keys.
+func RegisterTyped[D any](r *Registry, def FaultDefinition) (TypedConstructor[D], error) {
+	if err := checkDataType(reflect.TypeOf((*D)(nil)).Elem()); err != nil {
+		return nil, fmt.Errorf("error code %s: %w", def.Code, err)
+	}
+	constructor, err := r.Register(def)
+	if err != nil {
+		return nil, err
+	}
+	return func(data D, cause error) fault.Fault {
+		// A value only fails to encode on unsupported dynamic values, e.g. a
+		// NaN or a channel field; the fault then has no data.
+		m, _ := toData(data)
+		return constructor(m, cause)
+	}, nil
+}
+
+// MustRegisterTyped is RegisterTyped for package level variables.
+func MustRegisterTyped[D any](r *Registry, def FaultDefinition) TypedConstructor[D] {
+	constructor, err := RegisterTyped[D](r, def)
+	if err != nil {
+		panic(err)
+	}
+	return constructor
+}
+
+func checkDataType(t reflect.Type) error {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t.Kind() == reflect.Struct:
+		return nil
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String:
+		return nil
+	}
+	return fmt.Errorf("data type %s does not encode to a JSON object", t)
+}
+
+func toData(data any) (map[string]any, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(encoded, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+)
+
+type orderNotFound struct {
+	OrderId string `json:"order_id"`
+	Token   string `json:"token,omitempty"`
+}
+
+func TestRegisterTyped(t *testing.T) {
+	registry := NewRegistry()
+	newOrderNotFound, err := RegisterTyped[orderNotFound](registry, FaultDefinition{
+		Code:         "ORD0000000001",
+		Component:    ErrService,
+		ResponseType: NotFound,
+		Message:      "order {{.order_id}} not found",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cause := stderrors.New("no rows")
+	f := newOrderNotFound(orderNotFound{OrderId: "o-1", Token: "abc"}, cause)
+	defined := f.(*DefinedFault)
+	if defined.Message() != "order o-1 not found" {
+		t.Errorf("message = %q", defined.Message())
+	}
+	if defined.Data()["order_id"] != "o-1" || defined.Data()["token"] != Redacted {
+		t.Errorf("data = %v", defined.Data())
+	}
+	if !stderrors.Is(f, cause) {
+		t.Error("cause not unwrappable")
+	}
+	if _, ok := registry.Lookup("ORD0000000001"); !ok {
+		t.Error("definition not registered")
+	}
+}
+
+func TestRegisterTypedRejectsNonObjects(t *testing.T) {
+	_, err := RegisterTyped[[]string](NewRegistry(), FaultDefinition{Code: "ORD0000000002"})
+	if err == nil {
+		t.Error("a slice data type was accepted")
+	}
+}
+
+func TestMustRegisterTypedPanicsOnDuplicates(t *testing.T) {
+	registry := NewRegistry()
+	def := FaultDefinition{Code: fault.ErrorCode("ORD0000000003")}
+	MustRegisterTyped[orderNotFound](registry, def)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate code registered")
+		}
+	}()
+	MustRegisterTyped[orderNotFound](registry, def)
+}
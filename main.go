@@ -1,5 +1,20 @@
 package main
 
-func main() {
+import (
+	"log"
+
+	"github.com/PrathamSkilltelligent/pmgingo/server"
+	"github.com/gin-gonic/gin"
+)
 
+func main() {
+	srv := server.New(
+		server.WithAddr(":8080"),
+	)
+	srv.Engine.GET("/ping", func(c *gin.Context) {
+		c.JSON(200, gin.H{"data": "pong"})
+	})
+	if err := srv.Run(); err != nil {
+		log.Fatal(err)
+	}
 }
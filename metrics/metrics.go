@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Labels of the request metrics. ErrorCode is empty for requests that did
+// not end with a fault, Route is UnmatchedRoute for requests no route
+// matched so unknown paths do not each get a series.
+const (
+	RouteLabel     = "route"
+	MethodLabel    = "method"
+	StatusLabel    = "status"
+	ErrorCodeLabel = "error_code"
+	HandlerLabel   = "handler"
+	SourceLabel    = "source"
+	ReasonLabel    = "reason"
+
+	UnmatchedRoute = "unmatched"
+)
+
+// Values of HandlerLabel.
+const (
+	RequestHandler    = "request"
+	MiddlewareHandler = "middleware"
+)
+
+// Values of ReasonLabel.
+const (
+	// MissingReason is a mandatory parameter that was not sent.
+	MissingReason = "missing"
+	// InvalidReason is a parameter that does not convert to its type.
+	InvalidReason = "invalid"
+	// RejectedReason is a parameter its validator function rejected.
+	RejectedReason = "rejected"
+)
+
+var (
+	RequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Number of HTTP requests handled.",
+	}, []string{RouteLabel, MethodLabel, StatusLabel, ErrorCodeLabel})
+
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Time taken to handle HTTP requests.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{RouteLabel, MethodLabel, StatusLabel, ErrorCodeLabel})
+
+	PanicsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_handler_panics_total",
+		Help: "Number of panics recovered in HandleRequest and HandleMiddleware.",
+	}, []string{HandlerLabel})
+
+	ParameterValidationFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_parameter_validation_failures_total",
+		Help: "Number of request parameters that failed validation, by parameter source.",
+	}, []string{SourceLabel, ReasonLabel})
+)
+
+// Registry holds the library metrics along with the Go runtime and process
+// collectors. Applications register their own metrics on it to have them
+// served by Handler too.
+var Registry = newRegistry()
+
+func newRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		RequestsTotal,
+		RequestDuration,
+		PanicsTotal,
+		ParameterValidationFailuresTotal,
+	)
+	return r
+}
+
+// Handler serves the metrics of Registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/request"
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/PrathamSkilltelligent/pmgingo/types"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AccessLog writes one log line per request once the rest of the chain has
+// completed.
+func AccessLog(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("path", c.Request.URL.Path),
+			slog.String("route", c.FullPath()),
+			slog.Int("status", c.Writer.Status()),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("ip", c.ClientIP()),
+		}
+		if callId, f := request.GetValueFromGinContext[types.CallId](c, routeutils.CallIdKey).Get(); f == nil {
+			attrs = append(attrs, slog.String("call_id", uuid.UUID(*callId).String()))
+		}
+		logger.LogAttrs(c.Request.Context(), slog.LevelInfo, "request", attrs...)
+	}
+}
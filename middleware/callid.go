@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"github.com/PrathamSkilltelligent/pmgingo/request"
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/PrathamSkilltelligent/pmgingo/types"
+	"github.com/PrathamSkilltelligent/pmgo/utils"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const CallIdHeader = routeutils.CallIdHeader
+
+// CallId makes sure every request carries a call id. The id sent by the
+// caller in the call-id or x-call-id header is reused, then the one derived
+// from the trace id by Tracing, otherwise a new one is generated. The id is stored in the gin context under
+// routeutils.CallIdKey and echoed back in the x-call-id response header.
+func CallId() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		callId, f := routeutils.GetCallerId(c).Get()
+		if f != nil {
+			callId, f = request.GetValueFromGinContext[types.CallId](c, routeutils.CallIdKey).Get()
+		}
+		if f != nil {
+			callId = utils.ToPtr(types.CallId(uuid.New()))
+		}
+		c.Set(routeutils.CallIdKey, callId)
+		c.Header(CallIdHeader, uuid.UUID(*callId).String())
+		c.Next()
+	}
+}
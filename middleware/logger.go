@@ -0,0 +1,17 @@
+package middleware
+
+import (
+	"log/slog"
+
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/gin-gonic/gin"
+)
+
+// ContextLogger stores logger in the gin context under routeutils.LoggerKey,
+// the base of the RequestCtx logger of every request.
+func ContextLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(routeutils.LoggerKey, logger)
+		c.Next()
+	}
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics counts requests and observes their latency once the rest of the
+// chain has completed, labelled by route template, method, status and the
+// ErrorCode of the fault reported by routeutils.HandleRequest.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = metrics.UnmatchedRoute
+		}
+		var errorCode string
+		if f, ok := lastFault(c); ok {
+			errorCode = f.Code().String()
+		}
+		labels := []string{route, c.Request.Method, strconv.Itoa(c.Writer.Status()), errorCode}
+		metrics.RequestsTotal.WithLabelValues(labels...).Inc()
+		metrics.RequestDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+	}
+}
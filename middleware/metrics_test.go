@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgingo/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsCountsRequestsByRouteAndErrorCode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(Metrics())
+	engine.GET("/orders/:id", func(c *gin.Context) {
+		if c.Param("id") == "missing" {
+			f := errors.CircuitOpenError("orders")
+			_ = c.Error(f)
+			c.Status(errors.FaultStatusCode(f))
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	series := []struct {
+		name    string
+		counter prometheus.Counter
+		want    float64
+	}{
+		{"ok", metrics.RequestsTotal.WithLabelValues("/orders/:id", http.MethodGet, "200", ""), 2},
+		{"failed", metrics.RequestsTotal.WithLabelValues("/orders/:id", http.MethodGet, "503", errors.ErrCircuitOpen.String()), 1},
+		{"unmatched", metrics.RequestsTotal.WithLabelValues(metrics.UnmatchedRoute, http.MethodGet, "404", ""), 1},
+	}
+	before := make([]float64, len(series))
+	for i, s := range series {
+		before[i] = testutil.ToFloat64(s.counter)
+	}
+
+	for _, path := range []string{"/orders/1", "/orders/2", "/orders/missing", "/nowhere"} {
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	for i, s := range series {
+		if got := testutil.ToFloat64(s.counter) - before[i]; got != s.want {
+			t.Errorf("%s: counted %v requests, want %v", s.name, got, s.want)
+		}
+	}
+}
+
+func TestMetricsHandlerServesLibraryMetrics(t *testing.T) {
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if n, err := testutil.GatherAndCount(metrics.Registry, "go_goroutines"); err != nil || n != 1 {
+		t.Errorf("go collector: %d series, %v", n, err)
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery converts a panic anywhere below it in the chain into the same
+// 500 response HandleRequest produces, logging the stack trace.
+func Recovery(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if exception := recover(); exception != nil {
+				logger.Error("recovered from panic",
+					slog.String("error", fmt.Sprintf("%+v", exception)),
+					slog.String("path", c.Request.URL.Path),
+					slog.String("stack", string(debug.Stack())),
+				)
+				c.AbortWithStatusJSON(500, gin.H{
+					"Message": "Internal Server Error. Please Contact Admin.",
+				})
+			}
+		}()
+		c.Next()
+	}
+}
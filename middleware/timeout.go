@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout sets a deadline on the request context. It is installed for every
+// route with server.WithRequestTimeout, or on a single route ahead of
+// routeutils.HandleRequest, which answers with a RequestTimeoutError (504)
+// once the deadline passes. The earliest deadline wins: a route timeout can
+// shorten the global one but not extend it.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
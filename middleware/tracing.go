@@ -0,0 +1,140 @@
+package middleware
+
+import (
+	stderrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/PrathamSkilltelligent/pmgingo/types"
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const TracerName = "github.com/PrathamSkilltelligent/pmgingo/middleware"
+
+// Span attributes set on top of the OpenTelemetry HTTP conventions.
+const (
+	ErrorCodeAttribute = attribute.Key("fault.error_code")
+	CallIdAttribute    = attribute.Key("call_id")
+)
+
+type tracingOptions struct {
+	tracerProvider trace.TracerProvider
+	propagator     propagation.TextMapPropagator
+}
+
+type TracingOption func(*tracingOptions)
+
+// WithTracerProvider sets the provider spans are created from, the global
+// one by default. Tests pass a provider backed by an in-memory exporter.
+func WithTracerProvider(provider trace.TracerProvider) TracingOption {
+	return func(o *tracingOptions) {
+		o.tracerProvider = provider
+	}
+}
+
+// WithPropagator sets how the parent span is read from the request headers,
+// W3C traceparent by default.
+func WithPropagator(propagator propagation.TextMapPropagator) TracingOption {
+	return func(o *tracingOptions) {
+		o.propagator = propagator
+	}
+}
+
+// Tracing starts a server span per request, continuing the trace of the
+// traceparent header when there is one. The span is named after the route
+// template, carries the response status and the ErrorCode of the fault
+// reported by routeutils.HandleRequest, and is marked as errored on faults
+// and panics.
+//
+// The span is stored in the request context, so outbound calls made with the
+// context of a RequestCtx inject it in turn. A request arriving without a
+// call id gets the trace id as its call id; CallId must run after Tracing
+// for that.
+func Tracing(opts ...TracingOption) gin.HandlerFunc {
+	o := tracingOptions{
+		tracerProvider: otel.GetTracerProvider(),
+		propagator:     propagation.TraceContext{},
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	tracer := o.tracerProvider.Tracer(TracerName)
+
+	return func(c *gin.Context) {
+		ctx := o.propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		route := c.FullPath()
+		ctx, span := tracer.Start(ctx, spanName(c.Request.Method, route),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(c.Request.Method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(c.Request.URL.Path),
+				semconv.ClientAddress(c.ClientIP()),
+			),
+		)
+		defer span.End()
+		c.Request = c.Request.WithContext(ctx)
+
+		if traceId := span.SpanContext().TraceID(); traceId.IsValid() && !hasCallIdHeader(c) {
+			callId := types.CallId(uuid.UUID(traceId))
+			c.Set(routeutils.CallIdKey, &callId)
+		}
+
+		defer func() {
+			if exception := recover(); exception != nil {
+				span.SetAttributes(semconv.HTTPResponseStatusCode(http.StatusInternalServerError))
+				span.RecordError(fmt.Errorf("panic: %v", exception))
+				span.SetStatus(codes.Error, "panic")
+				panic(exception)
+			}
+		}()
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if callId, ok := c.Get(routeutils.CallIdKey); ok {
+			if callId, ok := callId.(*types.CallId); ok {
+				span.SetAttributes(CallIdAttribute.String(uuid.UUID(*callId).String()))
+			}
+		}
+		if f, ok := lastFault(c); ok {
+			span.SetAttributes(ErrorCodeAttribute.String(f.Code().String()))
+			span.RecordError(f)
+			span.SetStatus(codes.Error, f.Code().String())
+		} else if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+func spanName(method string, route string) string {
+	if route == "" {
+		return method
+	}
+	return method + " " + route
+}
+
+func hasCallIdHeader(c *gin.Context) bool {
+	return c.GetHeader(CallIdHeader) != "" || c.GetHeader("call-id") != ""
+}
+
+// lastFault returns the fault recorded on the gin context by
+// routeutils.HandleRequest or HandleMiddleware.
+func lastFault(c *gin.Context) (fault.Fault, bool) {
+	for i := len(c.Errors) - 1; i >= 0; i-- {
+		var f fault.Fault
+		if stderrors.As(c.Errors[i].Err, &f) {
+			return f, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newTracedEngine(t *testing.T) (*gin.Engine, *tracetest.InMemoryExporter) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	engine := gin.New()
+	engine.Use(Recovery(slog.New(slog.NewTextHandler(io.Discard, nil))), Tracing(WithTracerProvider(provider)), CallId())
+	return engine, exporter
+}
+
+func onlySpan(t *testing.T, exporter *tracetest.InMemoryExporter) tracetest.SpanStub {
+	t.Helper()
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	return spans[0]
+}
+
+func attributeValue(span tracetest.SpanStub, key attribute.Key) (attribute.Value, bool) {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTracingNamesSpanAfterRoute(t *testing.T) {
+	engine, exporter := newTracedEngine(t)
+	engine.GET("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	span := onlySpan(t, exporter)
+	if span.Name != "GET /users/:id" {
+		t.Errorf("name = %q", span.Name)
+	}
+	if span.SpanKind != trace.SpanKindServer {
+		t.Errorf("kind = %s", span.SpanKind)
+	}
+	if status, _ := attributeValue(span, semconv.HTTPResponseStatusCodeKey); status.AsInt64() != http.StatusNoContent {
+		t.Errorf("status attribute = %v", status.Emit())
+	}
+	if span.Status.Code == codes.Error {
+		t.Error("span marked as errored")
+	}
+}
+
+func TestTracingContinuesTraceparent(t *testing.T) {
+	engine, exporter := newTracedEngine(t)
+	engine.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	engine.ServeHTTP(httptest.NewRecorder(), req)
+
+	span := onlySpan(t, exporter)
+	if got := span.SpanContext.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace id = %s", got)
+	}
+	if got := span.Parent.SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("parent span id = %s", got)
+	}
+}
+
+func TestTracingDerivesCallIdFromTraceId(t *testing.T) {
+	engine, exporter := newTracedEngine(t)
+	engine.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	span := onlySpan(t, exporter)
+	callId, ok := attributeValue(span, CallIdAttribute)
+	if !ok {
+		t.Fatal("no call id attribute")
+	}
+	if callId.AsString() != rec.Header().Get(CallIdHeader) {
+		t.Errorf("call id attribute %s, header %s", callId.AsString(), rec.Header().Get(CallIdHeader))
+	}
+}
+
+func TestTracingRecordsFault(t *testing.T) {
+	engine, exporter := newTracedEngine(t)
+	engine.GET("/", func(c *gin.Context) {
+		f := errors.CircuitOpenError("orders")
+		_ = c.Error(f)
+		c.JSON(errors.FaultStatusCode(f), routeutils.NewErrorEnvelope(f))
+	})
+
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	span := onlySpan(t, exporter)
+	if code, _ := attributeValue(span, ErrorCodeAttribute); code.AsString() != errors.ErrCircuitOpen.String() {
+		t.Errorf("error code attribute = %q", code.AsString())
+	}
+	if span.Status.Code != codes.Error {
+		t.Errorf("status = %v", span.Status)
+	}
+}
+
+func TestTracingRecordsPanic(t *testing.T) {
+	engine, exporter := newTracedEngine(t)
+	engine.GET("/", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("response status = %d", rec.Code)
+	}
+	span := onlySpan(t, exporter)
+	if span.Status.Code != codes.Error || span.Status.Description != "panic" {
+		t.Errorf("status = %v", span.Status)
+	}
+	if status, _ := attributeValue(span, semconv.HTTPResponseStatusCodeKey); status.AsInt64() != http.StatusInternalServerError {
+		t.Errorf("status attribute = %v", status.Emit())
+	}
+}
@@ -0,0 +1,57 @@
+package ratelimit
+
+import (
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/PrathamSkilltelligent/pmgingo/types"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// KeyFunc returns the key a request is limited under. Requests without a
+// key, e.g. anonymous ones for ByUserId, are not limited.
+type KeyFunc func(c *gin.Context) (string, bool)
+
+// ByIp limits each client IP.
+func ByIp() KeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		return "ip:" + string(types.Ip(c.ClientIP())), true
+	}
+}
+
+// ByUserId limits each user, as set by the authentication middleware under
+// routeutils.UserIdKey.
+func ByUserId() KeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		userId, f := routeutils.GetUserId(c).Get()
+		if f != nil || uuid.UUID(*userId) == uuid.Nil {
+			return "", false
+		}
+		return "user:" + uuid.UUID(*userId).String(), true
+	}
+}
+
+// ByOrgId limits each organisation of the authenticated caller, as set
+// under routeutils.OrgIdsKey: the one named by the orgid path parameter when
+// the caller belongs to it, else the caller's only org. Other requests are
+// limited by client IP, so that naming another tenant in the path cannot
+// drain its bucket.
+func ByOrgId() KeyFunc {
+	return func(c *gin.Context) (string, bool) {
+		orgIds, f := routeutils.GetOrgIds(c).Get()
+		if f != nil || len(*orgIds) == 0 {
+			return ByIp()(c)
+		}
+		if len(*orgIds) == 1 {
+			return "org:" + uuid.UUID((*orgIds)[0]).String(), true
+		}
+		// Parsed by hand: an invalid parameter is the handler's to report.
+		if param, err := uuid.Parse(c.Param("orgid")); err == nil {
+			for _, orgId := range *orgIds {
+				if uuid.UUID(orgId) == param {
+					return "org:" + param.String(), true
+				}
+			}
+		}
+		return ByIp()(c)
+	}
+}
@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/gin-gonic/gin"
+)
+
+// Response headers, after the IETF RateLimit header fields draft.
+const (
+	LimitHeader      = "RateLimit-Limit"
+	RemainingHeader  = "RateLimit-Remaining"
+	ResetHeader      = "RateLimit-Reset"
+	RetryAfterHeader = "Retry-After"
+)
+
+type options struct {
+	key    KeyFunc
+	store  Store
+	logger *slog.Logger
+}
+
+type Option func(*options)
+
+// WithKey selects what requests are limited by. Defaults to ByIp.
+func WithKey(key KeyFunc) Option {
+	return func(o *options) {
+		o.key = key
+	}
+}
+
+// WithStore replaces the in-memory store, e.g. with a shared backend.
+func WithStore(store Store) Option {
+	return func(o *options) {
+		o.store = store
+	}
+}
+
+// WithLogger sets the logger store failures are reported to. Defaults to
+// slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// NewMiddleware limits requests with a token bucket per key. Every response
+// carries the RateLimit-* headers; a request over the limit is answered
+// with a RateLimitExceededError (429) and a Retry-After header.
+//
+// Limiting by user or org needs the middleware installed after the
+// authentication hooks. When the store fails the request is let through.
+// It fails when limit has no positive Requests and Period or a negative
+// Burst.
+func NewMiddleware(limit Limit, opts ...Option) (gin.HandlerFunc, error) {
+	if err := limit.validate(); err != nil {
+		return nil, err
+	}
+	o := options{
+		key:    ByIp(),
+		store:  NewMemoryStore(),
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(c *gin.Context) {
+		key, ok := o.key(c)
+		if !ok {
+			c.Next()
+			return
+		}
+		res, err := o.store.Take(c.Request.Context(), limit.String()+":"+key, limit, time.Now())
+		if err != nil {
+			o.logger.WarnContext(c.Request.Context(), "rate limit store failed", slog.String("error", err.Error()))
+			c.Next()
+			return
+		}
+
+		c.Header(LimitHeader, strconv.Itoa(res.Limit))
+		c.Header(RemainingHeader, strconv.Itoa(res.Remaining))
+		c.Header(ResetHeader, strconv.Itoa(ceilSeconds(res.Reset)))
+		if res.Allowed {
+			c.Next()
+			return
+		}
+
+		retryAfter := max(ceilSeconds(res.RetryAfter), 1)
+		c.Header(RetryAfterHeader, strconv.Itoa(retryAfter))
+		f := errors.RateLimitExceededError(retryAfter)
+		_ = c.Error(f)
+		c.AbortWithStatusJSON(errors.FaultStatusCode(f), routeutils.NewErrorEnvelope(f))
+	}, nil
+}
+
+// Middleware is NewMiddleware for limits fixed at compile time: it panics
+// when limit is invalid.
+func Middleware(limit Limit, opts ...Option) gin.HandlerFunc {
+	middleware, err := NewMiddleware(limit, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return middleware
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,143 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/PrathamSkilltelligent/pmgingo/types"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func serve(engine *gin.Engine, path string, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = ip + ":1234"
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec
+}
+
+func newLimitedEngine(handlers ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	ok := func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	}
+	engine.GET("/", append(handlers, ok)...)
+	return engine
+}
+
+func TestMiddlewareRejectsOverLimit(t *testing.T) {
+	engine := newLimitedEngine(Middleware(Limit{Requests: 2, Period: time.Minute}))
+
+	for i := 0; i < 2; i++ {
+		rec := serve(engine, "/", "10.0.0.1")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: %d", i, rec.Code)
+		}
+		if rec.Header().Get(LimitHeader) != "2" {
+			t.Errorf("limit header = %q", rec.Header().Get(LimitHeader))
+		}
+	}
+
+	rec := serve(engine, "/", "10.0.0.1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if rec.Header().Get(RemainingHeader) != "0" || rec.Header().Get(RetryAfterHeader) != "30" {
+		t.Errorf("headers = %v", rec.Header())
+	}
+	var env routeutils.ErrorEnvelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil || env.Error.ErrorCode != errors.ErrRateLimitExceeded.String() {
+		t.Errorf("body = %s", rec.Body.String())
+	}
+
+	if rec := serve(engine, "/", "10.0.0.2"); rec.Code != http.StatusOK {
+		t.Errorf("another client was limited: %d", rec.Code)
+	}
+}
+
+func TestMiddlewareSkipsRequestsWithoutKey(t *testing.T) {
+	engine := newLimitedEngine(Middleware(Limit{Requests: 1, Period: time.Minute}, WithKey(ByUserId())))
+
+	for i := 0; i < 3; i++ {
+		rec := serve(engine, "/", "10.0.0.1")
+		if rec.Code != http.StatusOK || rec.Header().Get(LimitHeader) != "" {
+			t.Fatalf("anonymous request %d: %d %v", i, rec.Code, rec.Header())
+		}
+	}
+}
+
+func TestMiddlewaresSharingAStoreKeepTheirLimits(t *testing.T) {
+	store := NewMemoryStore()
+	strict := Middleware(Limit{Requests: 1, Period: time.Minute}, WithStore(store))
+	loose := Middleware(Limit{Requests: 5, Period: time.Minute}, WithStore(store))
+	engine := newLimitedEngine(loose, strict)
+
+	if rec := serve(engine, "/", "10.0.0.1"); rec.Code != http.StatusOK {
+		t.Fatalf("first request: %d", rec.Code)
+	}
+	rec := serve(engine, "/", "10.0.0.1")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request: %d", rec.Code)
+	}
+}
+
+func TestNewMiddlewareRejectsInvalidLimits(t *testing.T) {
+	for _, limit := range []Limit{
+		{Requests: 10},
+		{Period: time.Second},
+		{Requests: 10, Period: time.Second, Burst: -1},
+	} {
+		if _, err := NewMiddleware(limit); err == nil {
+			t.Errorf("%+v accepted", limit)
+		}
+	}
+}
+
+func TestMiddlewarePanicsOnInvalidLimit(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("no panic for a limit without period")
+		}
+	}()
+	Middleware(Limit{Requests: 10})
+}
+
+func TestByOrgIdUsesTheAuthenticatedOrgs(t *testing.T) {
+	member, other := types.OrgId(uuid.New()), types.OrgId(uuid.New())
+	tests := []struct {
+		name   string
+		orgIds []types.OrgId
+		path   string
+		want   string
+	}{
+		{"anonymous", nil, "/orgs/" + uuid.UUID(other).String(), "ip:10.0.0.1"},
+		{"single org", []types.OrgId{member}, "/orgs/" + uuid.UUID(other).String(), "org:" + uuid.UUID(member).String()},
+		{"member of the path org", []types.OrgId{other, member}, "/orgs/" + uuid.UUID(member).String(), "org:" + uuid.UUID(member).String()},
+		{"not a member of the path org", []types.OrgId{member, types.OrgId(uuid.New())}, "/orgs/" + uuid.UUID(other).String(), "ip:10.0.0.1"},
+		{"invalid path org", []types.OrgId{member, other}, "/orgs/nope", "ip:10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			engine := gin.New()
+			var key string
+			engine.GET("/orgs/:orgid", func(c *gin.Context) {
+				if tt.orgIds != nil {
+					c.Set(routeutils.OrgIdsKey, &tt.orgIds)
+				}
+				key, _ = ByOrgId()(c)
+			})
+			serve(engine, tt.path, "10.0.0.1")
+			if key != tt.want {
+				t.Errorf("key = %q, want %q", key, tt.want)
+			}
+		})
+	}
+}
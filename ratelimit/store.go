@@ -0,0 +1,139 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit is a token bucket holding up to Burst tokens and refilled at
+// Requests per Period. Every request takes a token.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	// Burst is the bucket size. Defaults to Requests.
+	Burst int
+}
+
+// String identifies the limit, e.g. "100/1m0s/150". It prefixes the keys of
+// the buckets so that middleware with different limits sharing a store do
+// not share buckets.
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s/%d", l.Requests, l.Period, int(l.capacity()))
+}
+
+func (l Limit) validate() error {
+	switch {
+	case l.Requests <= 0:
+		return fmt.Errorf("ratelimit: Requests must be positive, got %d", l.Requests)
+	case l.Period <= 0:
+		return fmt.Errorf("ratelimit: Period must be positive, got %s", l.Period)
+	case l.Burst < 0:
+		return fmt.Errorf("ratelimit: Burst must not be negative, got %d", l.Burst)
+	}
+	return nil
+}
+
+func (l Limit) capacity() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return float64(l.Requests)
+}
+
+// rate is the refill rate in tokens per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result is the state of a bucket after a request tried to take a token.
+type Result struct {
+	Allowed bool
+	// Limit is the bucket size and Remaining the tokens left in it.
+	Limit     int
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next token when not Allowed.
+	RetryAfter time.Duration
+}
+
+// Store keeps the buckets. MemoryStore serves a single instance; services
+// running several instances plug in a shared backend, e.g. Redis.
+type Store interface {
+	// Take takes a token from the bucket of key, refilled according to
+	// limit up to now. The middleware prefixes key with the limit.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// bucket is the state of one key. full is when the bucket will be full
+// again, after which it can be dropped.
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+// DefaultSweepInterval is how often MemoryStore drops the buckets that
+// have refilled.
+const DefaultSweepInterval = time.Minute
+
+// MemoryStore keeps the buckets in memory. Buckets that have refilled are
+// dropped, so memory follows the number of recently active keys.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	capacity, rate := limit.capacity(), limit.rate()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rate)
+		b.updated = now
+	}
+
+	res := Result{Limit: int(capacity)}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = seconds((capacity - b.tokens) / rate)
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < DefaultSweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreRefillsOverTime(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 2, Period: time.Second}
+	now := time.Unix(1700000000, 0)
+
+	for i := 0; i < 2; i++ {
+		res, _ := store.Take(context.Background(), "k", limit, now)
+		if !res.Allowed {
+			t.Fatalf("request %d rejected", i)
+		}
+	}
+	res, _ := store.Take(context.Background(), "k", limit, now)
+	if res.Allowed || res.Remaining != 0 {
+		t.Fatalf("third request: %+v", res)
+	}
+	if res.RetryAfter != 500*time.Millisecond {
+		t.Errorf("retry after = %s", res.RetryAfter)
+	}
+
+	res, _ = store.Take(context.Background(), "k", limit, now.Add(500*time.Millisecond))
+	if !res.Allowed {
+		t.Errorf("request after a token refilled: %+v", res)
+	}
+}
+
+func TestMemoryStoreBurst(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 1, Period: time.Second, Burst: 3}
+	now := time.Unix(1700000000, 0)
+
+	allowed := 0
+	for i := 0; i < 5; i++ {
+		if res, _ := store.Take(context.Background(), "k", limit, now); res.Allowed {
+			allowed++
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("%d requests allowed, want the burst of 3", allowed)
+	}
+
+	res, _ := store.Take(context.Background(), "k", limit, now.Add(10*time.Second))
+	if !res.Allowed || res.Limit != 3 || res.Remaining != 2 {
+		t.Errorf("after a long pause: %+v", res)
+	}
+}
+
+func TestMemoryStoreKeysAreIndependent(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 1, Period: time.Minute}
+	now := time.Unix(1700000000, 0)
+
+	store.Take(context.Background(), "a", limit, now)
+	if res, _ := store.Take(context.Background(), "b", limit, now); !res.Allowed {
+		t.Error("key b limited by the requests of key a")
+	}
+}
+
+func TestMemoryStoreDropsRefilledBuckets(t *testing.T) {
+	store := NewMemoryStore()
+	limit := Limit{Requests: 10, Period: time.Second}
+	now := time.Unix(1700000000, 0)
+
+	store.Take(context.Background(), "a", limit, now)
+	store.Take(context.Background(), "b", limit, now.Add(DefaultSweepInterval))
+	if _, ok := store.buckets["a"]; ok {
+		t.Error("refilled bucket kept after a sweep")
+	}
+	if len(store.buckets) != 1 {
+		t.Errorf("%d buckets", len(store.buckets))
+	}
+}
+
+func TestLimitValidation(t *testing.T) {
+	for _, limit := range []Limit{
+		{Requests: 0, Period: time.Second},
+		{Requests: 1},
+		{Requests: 1, Period: time.Second, Burst: -1},
+	} {
+		if limit.validate() == nil {
+			t.Errorf("%+v accepted", limit)
+		}
+	}
+	if err := (Limit{Requests: 1, Period: time.Second}).validate(); err != nil {
+		t.Error(err)
+	}
+}
@@ -2,10 +2,11 @@ package request
 
 import (
 	"encoding/json"
-	"log"
+	"fmt"
 	"strconv"
 
 	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgingo/metrics"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/samber/mo"
@@ -78,6 +79,7 @@ func getParam[T ParamType](
 	}
 	if paramVal == "" {
 		if isMandatory {
+			countValidationFailure(source, metrics.MissingReason)
 			return mo.Err[*T](errors.ErrParameterNotFound(name))
 		} else {
 			//optional and hence return nil
@@ -87,7 +89,8 @@ func getParam[T ParamType](
 		convertResult := converter(paramVal)
 		convertPtr, err := convertResult.Get()
 		if err != nil {
-			return mo.Err[*T](errors.ErrInvalidParameter(name))
+			countValidationFailure(source, metrics.InvalidReason)
+			return mo.Err[*T](errors.ErrInvalidParameterWithCause(name, err))
 		}
 		converted := *convertPtr
 		if validator != nil {
@@ -96,6 +99,7 @@ func getParam[T ParamType](
 			if validated {
 				return mo.Ok[*T](&converted)
 			} else {
+				countValidationFailure(source, metrics.RejectedReason)
 				return mo.Err[*T](errors.ErrInvalidParameter(name))
 			}
 		} else {
@@ -105,6 +109,10 @@ func getParam[T ParamType](
 
 }
 
+func countValidationFailure(source ParameterSource, reason string) {
+	metrics.ParameterValidationFailuresTotal.WithLabelValues(source.String(), reason).Inc()
+}
+
 func GetIntegerParam(
 	c *gin.Context,
 	name string,
@@ -170,7 +178,6 @@ func GetUuidParam(
 ) mo.Result[*uuid.UUID] {
 	valResult := getParam(c, name, isMandatory, source, func(v string) mo.Result[*uuid.UUID] {
 		val, err := uuid.Parse(v)
-		log.Println(v, val, err)
 		if err != nil {
 			return mo.Err[*uuid.UUID](errors.ErrTypeCastFailed(name, v, "uuid", err))
 		} else {
@@ -215,9 +222,11 @@ func GetValueFromGinContext[T any](c *gin.Context, name string) mo.Result[*T] {
 		t, ok := val.(*T)
 		if !ok {
 			bval, _ := json.Marshal(val)
-			return mo.Err[*T](errors.ErrTypeCastFailed(name, string(bval), "bool", nil))
+			datatype := fmt.Sprintf("%T", t)
+			cause := fmt.Errorf("value of type %T is not a %s", val, datatype)
+			return mo.Err[*T](errors.ErrTypeCastFailed(name, string(bval), datatype, cause))
 		}
 		return mo.Ok(t)
 	}
-	return mo.Err[*T](errors.ErrGetValFromGinCtx(name, nil))
+	return mo.Err[*T](errors.ErrGetValFromGinCtx(name, fmt.Errorf("key %s is not set", name)))
 }
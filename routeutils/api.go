@@ -1,9 +1,13 @@
 package routeutils
 
 import (
-	"net/http"
+	"context"
+	"crypto/x509/pkix"
+	"fmt"
+	"log/slog"
 
 	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgingo/metrics"
 	"github.com/PrathamSkilltelligent/pmgingo/request"
 	"github.com/PrathamSkilltelligent/pmgingo/types"
 	"github.com/PrathamSkilltelligent/pmgo/fault"
@@ -12,12 +16,42 @@ import (
 	"github.com/samber/mo"
 )
 
+// CallIdKey is the gin context key the call id middleware stores the
+// request's *types.CallId under.
+const CallIdKey = "call_id"
+
+// CallIdHeader carries the call id between services.
+const CallIdHeader = "x-call-id"
+
+// UserIdKey and OrgIdsKey are the gin context keys authentication
+// middleware stores the caller's *types.UserId and *[]types.OrgId under.
+const (
+	UserIdKey = "user_id"
+	OrgIdsKey = "org_ids"
+)
+
+// LoggerKey is the gin context key middleware.ContextLogger stores the
+// *slog.Logger of the request under.
+const LoggerKey = "logger"
+
+// RequestCtx is what a handler gets to know about the request it handles.
+// It implements context.Context, derived from the request context and
+// carrying the call id, user id, org ids and logger, so that handlers can
+// pass it straight to DB and HTTP calls; those are cancelled when the client
+// disconnects or the request deadline passes.
 type RequestCtx struct {
 	GinCtx *gin.Context
 	IP     types.Ip
 	CallId types.CallId
 	UserId types.UserId
 	OrgIds []types.OrgId
+	// ClientCertSubject is the subject of the verified client certificate
+	// when the request came in over mutual TLS, nil otherwise.
+	ClientCertSubject *pkix.Name
+	// Logger is the request logger, with the call id and user id attached.
+	Logger *slog.Logger
+
+	ctx context.Context
 }
 
 func NewRequestCtx(
@@ -25,28 +59,43 @@ func NewRequestCtx(
 ) *RequestCtx {
 	ip := ginCtx.ClientIP()
 
-	// var callId *types.CallId
-	// callId, f := GetCallerId(ginCtx).Get()
-	// if f != nil {
-	// 	callId = (*types.CallId)(utils.ToPtr(uuid.New()))
-	// }
-
-	// userId, f := GetUserId(ginCtx).Get()
-	// if f != nil {
-	// 	userId = (*types.UserId)(utils.ToPtr(uuid.New()))
-	// }
-	// var orgIds []types.OrgId
-	// if ids, _ := GetOrgIds(ginCtx).Get(); ids != nil && len(*ids) > 0 {
-	// 	orgIds = *ids
-	// 	fmt.Println("orgIDs", uuid.UUID(orgIds[0]))
-	// }
-	return &RequestCtx{
-		GinCtx: ginCtx,
-		IP:     types.Ip(ip),
-		// CallId: *callId,
-		// UserId: *userId,
-		// OrgIds: orgIds,
+	var callId types.CallId
+	if id, f := request.GetValueFromGinContext[types.CallId](ginCtx, CallIdKey).Get(); f == nil {
+		callId = *id
+	}
+	var userId types.UserId
+	if id, f := request.GetValueFromGinContext[types.UserId](ginCtx, UserIdKey).Get(); f == nil {
+		userId = *id
+	}
+	var orgIds []types.OrgId
+	if ids, f := request.GetValueFromGinContext[[]types.OrgId](ginCtx, OrgIdsKey).Get(); f == nil {
+		orgIds = *ids
+	}
+	logger := slog.Default()
+	if l, f := request.GetValueFromGinContext[slog.Logger](ginCtx, LoggerKey).Get(); f == nil {
+		logger = l
+	}
+
+	r := &RequestCtx{
+		GinCtx:            ginCtx,
+		IP:                types.Ip(ip),
+		CallId:            callId,
+		UserId:            userId,
+		OrgIds:            orgIds,
+		ClientCertSubject: getClientCertSubject(ginCtx),
+		Logger:            requestLogger(logger, callId, userId),
 	}
+	r.ctx = r.newContext()
+	return r
+}
+
+func getClientCertSubject(c *gin.Context) *pkix.Name {
+	state := c.Request.TLS
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	subject := state.VerifiedChains[0][0].Subject
+	return &subject
 }
 
 type ApplicationContext interface {
@@ -68,9 +117,9 @@ func HandleRequest[C ApplicationContext, T any](ctx C, handler ApiRequestHandler
 				if res.IsError() {
 					_, f := res.Get()
 					originalErr, _ := f.(fault.Fault) //No need to check for type assertion success, since we know that upstream will always provide fault.Fault
-					status := getStatusCode(originalErr.ResponseErrType())
-					// res := getErrorResponse(originalErr, ctx.GetFaultBundle())
-					c.JSON(status, res)
+					status := errors.FaultStatusCode(originalErr)
+					_ = c.Error(originalErr)
+					c.JSON(status, NewErrorEnvelope(originalErr))
 					c.Request.Body.Close() // #nosec G104
 				} else {
 					responseData, _ := res.Get()
@@ -96,6 +145,8 @@ func HandleRequest[C ApplicationContext, T any](ctx C, handler ApiRequestHandler
 				// } else {
 				// 	ctx.GetLogger().Error(fmt.Sprintln("Recovered from panic but couldn't retrieve file name and line number"), nil, nil, nil, nil, nil)
 				// }
+				metrics.PanicsTotal.WithLabelValues(metrics.RequestHandler).Inc()
+				_ = c.Error(errors.InternalServerError(fmt.Errorf("panic: %v", exception)))
 				c.AbortWithStatusJSON(500, gin.H{
 					"Message": "Internal Server Error. Please Contact Admin.",
 				})
@@ -104,40 +155,10 @@ func HandleRequest[C ApplicationContext, T any](ctx C, handler ApiRequestHandler
 		}()
 
 		reqCtx := NewRequestCtx(c)
-		res = handler(ctx, reqCtx)
+		res = runHandler(ctx, handler, reqCtx)
 	}
 }
 
-func getStatusCode(response fault.ResponseErrType) int {
-	switch response {
-	case errors.BadRequest:
-		return http.StatusBadRequest
-	case errors.Unauthorized:
-		return http.StatusUnauthorized
-	case errors.Forbidden:
-		return http.StatusForbidden
-	case errors.NotFound:
-		return http.StatusNotFound
-	case errors.AlreadyExists:
-		return http.StatusConflict
-	}
-	return http.StatusInternalServerError
-}
-
-// func getErrorResponse(f fault.Fault) map[string]any {
-// 	var errors []string
-// 	for _, cause := range f.Causes() {
-// 		errors = append(errors, cause.Error())
-// 	}
-// 	return map[string]any{
-// 		"errors": map[string]any{
-// 			// "message":     f.ToMessageAwareFault(fb).Message("en"),
-// 			"errorCode":   f.Code().String(),
-// 			"otherErrors": errors,
-// 		},
-// 	}
-// }
-
 type ApiMiddlewareHandler[C ApplicationContext] func(C, *gin.Context) mo.Result[*bool]
 
 func HandleMiddleware[C ApplicationContext](ctx C, middlewareHandler ApiMiddlewareHandler[C]) gin.HandlerFunc {
@@ -149,9 +170,9 @@ func HandleMiddleware[C ApplicationContext](ctx C, middlewareHandler ApiMiddlewa
 				if res.IsError() {
 					_, f := res.Get()
 					originalF, _ := f.(fault.Fault)
-					status := getStatusCode(originalF.ResponseErrType())
-					// res := getErrorResponse(originalF, ctx.GetFaultBundle())
-					c.AbortWithStatusJSON(status, res)
+					status := errors.FaultStatusCode(originalF)
+					_ = c.Error(originalF)
+					c.AbortWithStatusJSON(status, NewErrorEnvelope(originalF))
 					c.Request.Body.Close() // #nosec G104
 				} else {
 					c.Next()
@@ -166,6 +187,8 @@ func HandleMiddleware[C ApplicationContext](ctx C, middlewareHandler ApiMiddlewa
 				// } else {
 				// 	ctx.GetLogger().Error(fmt.Sprintln("Recovered from panic but couldn't retrieve file name and line number"), nil, nil, nil, nil, nil)
 				// }
+				metrics.PanicsTotal.WithLabelValues(metrics.MiddlewareHandler).Inc()
+				_ = c.Error(errors.InternalServerError(fmt.Errorf("panic: %v", exception)))
 				c.AbortWithStatusJSON(500, gin.H{
 					"Message": "Internal Server Error. Please Contact Admin.",
 				})
@@ -206,31 +229,33 @@ func GetUnixTimeFromParam(paramName string, isMandatory bool, c *gin.Context) mo
 }
 
 func GetCallerId(c *gin.Context) mo.Result[*types.CallId] {
-	callerIdResult := request.GetUuidParam(c, "call-id", request.HttpHeader, true)
-	if callerIdResult.IsError() {
-		callerIdResult = request.GetUuidParam(c, "x-call-id", request.HttpHeader, true)
-		if callerIdResult.IsError() {
-			_, err := callerIdResult.Get()
-			originalErr, _ := err.(fault.Fault)
-			return mo.Err[*types.CallId](errors.GetCallerIdError("x-call-id or call-id", originalErr.Cause()))
-		}
-	}
-	callId, _ := callerIdResult.Get()
+	// Both headers are optional on their own: a missing one is not counted as
+	// a parameter validation failure.
+	callerIdResult := request.GetUuidParam(c, "call-id", request.HttpHeader, false)
+	if callId, _ := callerIdResult.Get(); callId == nil {
+		callerIdResult = request.GetUuidParam(c, CallIdHeader, request.HttpHeader, false)
+	}
+	callId, err := callerIdResult.Get()
+	if err == nil && callId == nil {
+		err = errors.ErrParameterNotFound(CallIdHeader)
+	}
+	if err != nil {
+		return mo.Err[*types.CallId](errors.GetCallerIdError("x-call-id or call-id", err))
+	}
 	return mo.Ok(utils.ToPtr(types.CallId(*callId)))
 }
 
 func GetUserId(c *gin.Context) mo.Result[*types.UserId] {
-	userIdResult := request.GetValueFromGinContext[types.UserId](c, "user_id")
+	userIdResult := request.GetValueFromGinContext[types.UserId](c, UserIdKey)
 	if userIdResult.IsError() {
 		_, err := userIdResult.Get()
-		originalErr, _ := err.(fault.Fault)
-		return mo.Err[*types.UserId](errors.GetUserIdError("user_id", originalErr.Cause()))
+		return mo.Err[*types.UserId](errors.GetUserIdError(UserIdKey, err))
 	}
 	return userIdResult
 }
 
 func GetOrgIds(c *gin.Context) mo.Result[*[]types.OrgId] {
-	orgIds, f := request.GetValueFromGinContext[[]types.OrgId](c, "org_ids").Get()
+	orgIds, f := request.GetValueFromGinContext[[]types.OrgId](c, OrgIdsKey).Get()
 	if f != nil {
 		return mo.Err[*[]types.OrgId](f)
 	}
@@ -240,9 +265,8 @@ func GetOrgIds(c *gin.Context) mo.Result[*[]types.OrgId] {
 func GetOrgIdFromParam(c *gin.Context) mo.Result[*types.OrgId] {
 	orgIdResult := request.GetUuidParam(c, "orgid", request.PathParameter, true)
 	if orgIdResult.IsError() {
-		_, originalErr := orgIdResult.Get()
-		err, _ := originalErr.(fault.Fault)
-		return mo.Err[*types.OrgId](errors.GetOrgIdFromParamError(err.Cause()))
+		_, err := orgIdResult.Get()
+		return mo.Err[*types.OrgId](errors.GetOrgIdFromParamError(err))
 	}
 	orgId, _ := orgIdResult.Get()
 	return mo.Ok((*types.OrgId)(orgId))
@@ -0,0 +1,134 @@
+package routeutils
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/types"
+	"github.com/google/uuid"
+)
+
+var _ context.Context = (*RequestCtx)(nil)
+
+type contextKey int
+
+const (
+	callIdContextKey contextKey = iota
+	incomingHeaderContextKey
+	userIdContextKey
+	orgIdsContextKey
+	loggerContextKey
+)
+
+func ContextWithCallId(ctx context.Context, callId types.CallId) context.Context {
+	return context.WithValue(ctx, callIdContextKey, callId)
+}
+
+func CallIdFromContext(ctx context.Context) (types.CallId, bool) {
+	callId, ok := ctx.Value(callIdContextKey).(types.CallId)
+	return callId, ok
+}
+
+func ContextWithUserId(ctx context.Context, userId types.UserId) context.Context {
+	return context.WithValue(ctx, userIdContextKey, userId)
+}
+
+func UserIdFromContext(ctx context.Context) (types.UserId, bool) {
+	userId, ok := ctx.Value(userIdContextKey).(types.UserId)
+	return userId, ok
+}
+
+func ContextWithOrgIds(ctx context.Context, orgIds []types.OrgId) context.Context {
+	return context.WithValue(ctx, orgIdsContextKey, orgIds)
+}
+
+func OrgIdsFromContext(ctx context.Context) ([]types.OrgId, bool) {
+	orgIds, ok := ctx.Value(orgIdsContextKey).([]types.OrgId)
+	return orgIds, ok
+}
+
+func ContextWithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, logger)
+}
+
+// LoggerFromContext returns the request logger attached to ctx, or
+// slog.Default() when there is none.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+// ContextWithIncomingHeader attaches the headers of the request being handled,
+// so outbound calls can forward some of them.
+func ContextWithIncomingHeader(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, incomingHeaderContextKey, header)
+}
+
+func IncomingHeaderFromContext(ctx context.Context) (http.Header, bool) {
+	header, ok := ctx.Value(incomingHeaderContextKey).(http.Header)
+	return header, ok
+}
+
+// Context returns the request context carrying the call id, user id, org
+// ids, logger and headers of the request, to pass to the outbound client and
+// other context aware calls. RequestCtx delegates to it to implement
+// context.Context.
+func (r *RequestCtx) Context() context.Context {
+	if r.ctx != nil {
+		return r.ctx
+	}
+	return r.newContext()
+}
+
+func (r *RequestCtx) newContext() context.Context {
+	ctx := context.Background()
+	if r.GinCtx != nil && r.GinCtx.Request != nil {
+		ctx = ContextWithIncomingHeader(r.GinCtx.Request.Context(), r.GinCtx.Request.Header)
+	}
+	ctx = ContextWithCallId(ctx, r.CallId)
+	if uuid.UUID(r.UserId) != uuid.Nil {
+		ctx = ContextWithUserId(ctx, r.UserId)
+	}
+	if len(r.OrgIds) > 0 {
+		ctx = ContextWithOrgIds(ctx, r.OrgIds)
+	}
+	if r.Logger != nil {
+		ctx = ContextWithLogger(ctx, r.Logger)
+	}
+	return ctx
+}
+
+// Deadline returns the deadline set on the request, by middleware.Timeout
+// for instance. HandleRequest answers with a RequestTimeoutError once it
+// passes.
+func (r *RequestCtx) Deadline() (time.Time, bool) {
+	return r.Context().Deadline()
+}
+
+// Done is closed when the client disconnects or the request deadline passes.
+func (r *RequestCtx) Done() <-chan struct{} {
+	return r.Context().Done()
+}
+
+func (r *RequestCtx) Err() error {
+	return r.Context().Err()
+}
+
+func (r *RequestCtx) Value(key any) any {
+	return r.Context().Value(key)
+}
+
+// requestLogger attaches the ids of the request to logger.
+func requestLogger(logger *slog.Logger, callId types.CallId, userId types.UserId) *slog.Logger {
+	if uuid.UUID(callId) != uuid.Nil {
+		logger = logger.With(slog.String("call_id", uuid.UUID(callId).String()))
+	}
+	if uuid.UUID(userId) != uuid.Nil {
+		logger = logger.With(slog.String("user_id", uuid.UUID(userId).String()))
+	}
+	return logger
+}
@@ -0,0 +1,56 @@
+package routeutils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/PrathamSkilltelligent/pmgingo/types"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequestCtxCarriesRequestIdentity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	callId := types.CallId(uuid.New())
+	userId := types.UserId(uuid.New())
+	orgIds := []types.OrgId{types.OrgId(uuid.New())}
+	c.Set(CallIdKey, &callId)
+	c.Set(UserIdKey, &userId)
+	c.Set(OrgIdsKey, &orgIds)
+
+	var ctx context.Context = NewRequestCtx(c)
+
+	if got, ok := CallIdFromContext(ctx); !ok || got != callId {
+		t.Errorf("call id = %v, %v", got, ok)
+	}
+	if got, ok := UserIdFromContext(ctx); !ok || got != userId {
+		t.Errorf("user id = %v, %v", got, ok)
+	}
+	if got, ok := OrgIdsFromContext(ctx); !ok || len(got) != 1 || got[0] != orgIds[0] {
+		t.Errorf("org ids = %v, %v", got, ok)
+	}
+	if LoggerFromContext(ctx) == nil {
+		t.Error("no logger")
+	}
+}
+
+func TestRequestCtxFollowsRequestCancellation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	requestCtx, cancel := context.WithCancel(context.Background())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(requestCtx)
+
+	reqCtx := NewRequestCtx(c)
+	if reqCtx.Err() != nil {
+		t.Fatal("done before the request")
+	}
+	cancel()
+	<-reqCtx.Done()
+	if reqCtx.Err() != context.Canceled {
+		t.Errorf("err = %v", reqCtx.Err())
+	}
+}
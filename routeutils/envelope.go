@@ -0,0 +1,32 @@
+package routeutils
+
+import (
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgo/fault"
+)
+
+// ErrorEnvelope is the body written for a fault by HandleRequest and
+// HandleMiddleware. It keeps the {"error": {"message": ...}} shape of a
+// failed mo.Result and adds the error code and the (redacted) fault data, so
+// that callers built on this library can decode the fault again.
+type ErrorEnvelope struct {
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Message   string         `json:"message"`
+	ErrorCode string         `json:"errorCode,omitempty"`
+	Data      map[string]any `json:"data,omitempty"`
+}
+
+func NewErrorEnvelope(f fault.Fault) ErrorEnvelope {
+	body := ErrorBody{
+		Message:   f.Error(),
+		ErrorCode: f.Code().String(),
+	}
+	if defined, ok := f.(*errors.DefinedFault); ok {
+		body.Message = defined.Message()
+		body.Data = defined.Data()
+	}
+	return ErrorEnvelope{Error: body}
+}
@@ -0,0 +1,29 @@
+package routeutils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+)
+
+func TestErrorEnvelopeCarriesCodeMessageAndData(t *testing.T) {
+	encoded, err := json.Marshal(NewErrorEnvelope(errors.ErrTypeCastFailed("password", "hunter2", "uint64", nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var env ErrorEnvelope
+	if err := json.Unmarshal(encoded, &env); err != nil {
+		t.Fatal(err)
+	}
+	if env.Error.ErrorCode != errors.ErrTypeCast.String() {
+		t.Errorf("error code = %q", env.Error.ErrorCode)
+	}
+	if env.Error.Data["name"] != "password" || env.Error.Data["val"] != errors.Redacted {
+		t.Errorf("data = %v", env.Error.Data)
+	}
+	if want := "Error failed to cast password having value " + errors.Redacted + " to uint64"; env.Error.Message != want {
+		t.Errorf("message = %q, want %q", env.Error.Message, want)
+	}
+}
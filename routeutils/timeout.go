@@ -0,0 +1,186 @@
+package routeutils
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgingo/metrics"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/mo"
+)
+
+// handlerOutcome is what a handler run in its own goroutine reports back.
+type handlerOutcome[T any] struct {
+	res       mo.Result[*T]
+	exception any
+}
+
+// get returns the result, re-raising a panic of the handler so that
+// HandleRequest recovers it as if the handler had run inline.
+func (o handlerOutcome[T]) get() mo.Result[*T] {
+	if o.exception != nil {
+		panic(o.exception)
+	}
+	return o.res
+}
+
+// runHandler calls the handler inline, or, when the request context has a
+// deadline, in its own goroutine so that a RequestTimeoutError is returned
+// as soon as the deadline passes.
+//
+// A handler that overran its deadline keeps running until it returns, on a
+// copy of the gin context (see gin.Context.Copy) since gin reuses the
+// original for the next request. What it writes to the copy is buffered and
+// only replayed on the response when it returns in time, so a late handler
+// never writes a second response; likewise the keys it set and the errors it
+// recorded on the copy are carried over to the original only then. Handlers
+// should watch the request context and give up once it is done.
+func runHandler[C ApplicationContext, T any](ctx C, handler ApiRequestHandler[C, T], reqCtx *RequestCtx) mo.Result[*T] {
+	c := reqCtx.GinCtx
+	requestCtx := c.Request.Context()
+	if _, ok := requestCtx.Deadline(); !ok {
+		return handler(ctx, reqCtx)
+	}
+
+	writer := newBufferedWriter()
+	detached := *reqCtx
+	detached.GinCtx = c.Copy()
+	detached.GinCtx.Writer = writer
+
+	var abandoned atomic.Bool
+	done := make(chan handlerOutcome[T], 1)
+	go func() {
+		var outcome handlerOutcome[T]
+		defer func() {
+			outcome.exception = recover()
+			done <- outcome
+			if outcome.exception != nil && abandoned.Load() {
+				metrics.PanicsTotal.WithLabelValues(metrics.RequestHandler).Inc()
+			}
+		}()
+		outcome.res = handler(ctx, &detached)
+	}()
+
+	complete := func(outcome handlerOutcome[T]) mo.Result[*T] {
+		for key, value := range detached.GinCtx.Keys {
+			c.Set(key, value)
+		}
+		c.Errors = append(c.Errors, detached.GinCtx.Errors...)
+		if outcome.exception == nil {
+			writer.replay(c.Writer)
+		}
+		return outcome.get()
+	}
+
+	select {
+	case outcome := <-done:
+		return complete(outcome)
+	case <-requestCtx.Done():
+	}
+	if !stderrors.Is(requestCtx.Err(), context.DeadlineExceeded) {
+		// The client went away: there is nobody to answer, the handler sees
+		// the cancellation through its context.
+		return complete(<-done)
+	}
+
+	abandoned.Store(true)
+	select {
+	case outcome := <-done:
+		return complete(outcome)
+	default:
+		return mo.Err[*T](errors.RequestTimeoutError(c.FullPath(), requestCtx.Err()))
+	}
+}
+
+// errHijackUnsupported is returned by bufferedWriter.Hijack.
+var errHijackUnsupported = stderrors.New("routeutils: connection cannot be hijacked by a handler running with a request timeout")
+
+// bufferedWriter stands in for the response writer of a handler running
+// against a deadline. Hijacking the connection fails, CloseNotify never
+// fires (the request context is cancelled instead) and HTTP/2 push is not
+// available through it.
+type bufferedWriter struct {
+	header  http.Header
+	status  int
+	body    bytes.Buffer
+	written bool
+}
+
+var _ gin.ResponseWriter = (*bufferedWriter)(nil)
+
+func newBufferedWriter() *bufferedWriter {
+	return &bufferedWriter{header: http.Header{}, status: http.StatusOK}
+}
+
+func (w *bufferedWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedWriter) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *bufferedWriter) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *bufferedWriter) Status() int {
+	return w.status
+}
+
+func (w *bufferedWriter) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *bufferedWriter) Written() bool {
+	return w.written
+}
+
+func (w *bufferedWriter) Flush() {}
+
+func (w *bufferedWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, errHijackUnsupported
+}
+
+func (w *bufferedWriter) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+func (w *bufferedWriter) Pusher() http.Pusher {
+	return nil
+}
+
+// replay copies the buffered headers, and the status and body when the
+// handler wrote them, onto dst.
+func (w *bufferedWriter) replay(dst gin.ResponseWriter) {
+	for key, values := range w.header {
+		dst.Header()[key] = values
+	}
+	if !w.written {
+		return
+	}
+	dst.WriteHeader(w.status)
+	dst.WriteHeaderNow()
+	_, _ = dst.Write(w.body.Bytes())
+}
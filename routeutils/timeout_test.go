@@ -0,0 +1,131 @@
+package routeutils
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/samber/mo"
+)
+
+type testAppCtx struct{}
+
+func (testAppCtx) IsApplicationContext() {}
+
+type greeting struct {
+	Message string `json:"message"`
+}
+
+// newTimedEngine serves handler on "/" with a request deadline of timeout.
+// after is run once the rest of the chain has completed.
+func newTimedEngine(timeout time.Duration, after gin.HandlerFunc, handler ApiRequestHandler[testAppCtx, greeting]) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+		if after != nil {
+			after(c)
+		}
+	})
+	engine.GET("/", HandleRequest(testAppCtx{}, handler))
+	return engine
+}
+
+func TestTimedHandlerAnswersInTime(t *testing.T) {
+	var keySeen bool
+	var errorsSeen int
+	engine := newTimedEngine(time.Second, func(c *gin.Context) {
+		_, keySeen = c.Get("greeted")
+		errorsSeen = len(c.Errors)
+	}, func(_ testAppCtx, reqCtx *RequestCtx) mo.Result[*greeting] {
+		reqCtx.GinCtx.Header("X-Greeting", "hello")
+		reqCtx.GinCtx.Set("greeted", true)
+		_ = reqCtx.GinCtx.Error(errors.InternalServerError(nil))
+		return mo.Ok(&greeting{Message: "hello"})
+	})
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var body struct {
+		Data greeting `json:"data"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || body.Data.Message != "hello" {
+		t.Errorf("body = %s", rec.Body.String())
+	}
+	if rec.Header().Get("X-Greeting") != "hello" {
+		t.Error("header set by the handler was not replayed")
+	}
+	if !keySeen || errorsSeen != 1 {
+		t.Errorf("key seen %v, %d errors seen, want the ones set by the handler", keySeen, errorsSeen)
+	}
+}
+
+func TestTimedHandlerOverrunsDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	var keySeen bool
+	engine := newTimedEngine(20*time.Millisecond, func(c *gin.Context) {
+		_, keySeen = c.Get("late")
+	}, func(_ testAppCtx, reqCtx *RequestCtx) mo.Result[*greeting] {
+		<-release
+		reqCtx.GinCtx.Header("X-Late", "true")
+		reqCtx.GinCtx.Set("late", true)
+		return mo.Ok(&greeting{Message: "too late"})
+	})
+
+	start := time.Now()
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("answered after %s", elapsed)
+	}
+	if rec.Code != http.StatusGatewayTimeout {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	var env ErrorEnvelope
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil || env.Error.ErrorCode != errors.ErrRequestTimeout.String() {
+		t.Errorf("body = %s", rec.Body.String())
+	}
+	if rec.Header().Get("X-Late") != "" || keySeen {
+		t.Error("a late handler leaked into the request")
+	}
+}
+
+func TestTimedHandlerPanics(t *testing.T) {
+	engine := newTimedEngine(time.Second, nil, func(testAppCtx, *RequestCtx) mo.Result[*greeting] {
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d", rec.Code)
+	}
+}
+
+func TestBufferedWriterUnsupportedFeatures(t *testing.T) {
+	w := newBufferedWriter()
+	if _, _, err := w.Hijack(); err == nil {
+		t.Error("Hijack succeeded")
+	}
+	if w.CloseNotify() == nil {
+		t.Error("CloseNotify returned a nil channel")
+	}
+	if w.Pusher() != nil {
+		t.Error("Pusher is available")
+	}
+}
@@ -0,0 +1,275 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	HealthzPath = "/healthz"
+	LivezPath   = "/livez"
+	ReadyzPath  = "/readyz"
+
+	DefaultCheckTimeout = 2 * time.Second
+)
+
+const (
+	StatusOk       = "ok"
+	StatusFail     = "fail"
+	StatusDraining = "draining"
+)
+
+// Checker verifies a single dependency such as a database, a cache or a
+// downstream service. Check must honour ctx cancellation.
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type checkerFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (c checkerFunc) Name() string {
+	return c.name
+}
+
+func (c checkerFunc) Check(ctx context.Context) error {
+	return c.fn(ctx)
+}
+
+// NewChecker adapts a function, e.g. (*sql.DB).PingContext, into a Checker.
+func NewChecker(name string, fn func(ctx context.Context) error) Checker {
+	return checkerFunc{name: name, fn: fn}
+}
+
+type checkOptions struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+}
+
+type CheckOption func(*checkOptions)
+
+// WithCheckTimeout bounds a single run of the check. Defaults to
+// DefaultCheckTimeout.
+func WithCheckTimeout(timeout time.Duration) CheckOption {
+	return func(o *checkOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithCheckCacheTTL reuses the last result for ttl so that frequent probes
+// do not hammer the dependency. Defaults to no caching.
+func WithCheckCacheTTL(ttl time.Duration) CheckOption {
+	return func(o *checkOptions) {
+		o.cacheTTL = ttl
+	}
+}
+
+// CheckResult is the outcome of one check as reported by the endpoints.
+// Cached tells that the result is the last one of a check with a cache TTL.
+type CheckResult struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	Cached    bool    `json:"cached"`
+}
+
+// HealthReport is the body of the health endpoints. Status is StatusOk when
+// every check passed, StatusDraining on /readyz while draining, else
+// StatusFail.
+type HealthReport struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+type registeredCheck struct {
+	checker Checker
+	opts    checkOptions
+
+	mu        sync.Mutex
+	last      CheckResult
+	checkedAt time.Time
+	// inflight is closed when the check currently running completes.
+	inflight chan struct{}
+}
+
+// run returns the result of the check. With caching, a single run is in
+// flight at a time: concurrent probes get the last result meanwhile, or
+// wait for the first one.
+func (r *registeredCheck) run(ctx context.Context) CheckResult {
+	if r.opts.cacheTTL <= 0 {
+		return r.check(ctx)
+	}
+
+	r.mu.Lock()
+	if !r.checkedAt.IsZero() && (time.Since(r.checkedAt) < r.opts.cacheTTL || r.inflight != nil) {
+		cached := r.last
+		r.mu.Unlock()
+		cached.Cached = true
+		return cached
+	}
+	if inflight := r.inflight; inflight != nil {
+		r.mu.Unlock()
+		<-inflight
+		r.mu.Lock()
+		cached := r.last
+		r.mu.Unlock()
+		cached.Cached = true
+		return cached
+	}
+	inflight := make(chan struct{})
+	r.inflight = inflight
+	r.mu.Unlock()
+
+	result := r.check(ctx)
+
+	r.mu.Lock()
+	r.last = result
+	r.checkedAt = time.Now()
+	r.inflight = nil
+	r.mu.Unlock()
+	close(inflight)
+	return result
+}
+
+// check runs the checker on a context detached from the prober's request,
+// so that a prober hanging up does not turn into a (cached) failure.
+func (r *registeredCheck) check(ctx context.Context) CheckResult {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.opts.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := runCheck(ctx, r.checker)
+	result := CheckResult{
+		Name:      r.checker.Name(),
+		Status:    StatusOk,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// runCheck runs the checker until ctx is done. A panicking checker fails the
+// check rather than the process.
+func runCheck(ctx context.Context, checker Checker) error {
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if exception := recover(); exception != nil {
+				result <- fmt.Errorf("panic: %+v", exception)
+			}
+		}()
+		result <- checker.Check(ctx)
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Health serves /healthz, /livez and /readyz. Liveness checks decide whether
+// the process should be restarted, readiness checks whether it should get
+// traffic; /healthz runs both. /readyz also reports 503 while draining.
+type Health struct {
+	readiness *Readiness
+
+	mu              sync.RWMutex
+	livenessChecks  []*registeredCheck
+	readinessChecks []*registeredCheck
+}
+
+// NewHealth reports readiness as draining from /readyz once readiness is
+// draining; readiness may be nil.
+func NewHealth(readiness *Readiness) *Health {
+	return &Health{readiness: readiness}
+}
+
+// AddLivenessCheck adds a check to /livez and /healthz. It may be called while
+// the endpoints are being served.
+func (h *Health) AddLivenessCheck(checker Checker, opts ...CheckOption) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.livenessChecks = append(h.livenessChecks, newRegisteredCheck(checker, opts))
+}
+
+// AddReadinessCheck adds a check to /readyz and /healthz. It may be called
+// while the endpoints are being served.
+func (h *Health) AddReadinessCheck(checker Checker, opts ...CheckOption) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.readinessChecks = append(h.readinessChecks, newRegisteredCheck(checker, opts))
+}
+
+func newRegisteredCheck(checker Checker, opts []CheckOption) *registeredCheck {
+	o := checkOptions{timeout: DefaultCheckTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &registeredCheck{checker: checker, opts: o}
+}
+
+// Register adds the three endpoints to the given router.
+func (h *Health) Register(router gin.IRoutes) {
+	router.GET(HealthzPath, h.handler(true, true))
+	router.GET(LivezPath, h.handler(true, false))
+	router.GET(ReadyzPath, h.handler(false, true))
+}
+
+func (h *Health) handler(liveness bool, readiness bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var checks []*registeredCheck
+		h.mu.RLock()
+		if liveness {
+			checks = append(checks, h.livenessChecks...)
+		}
+		if readiness {
+			checks = append(checks, h.readinessChecks...)
+		}
+		h.mu.RUnlock()
+
+		report := runChecks(c.Request.Context(), checks)
+		if readiness && h.readiness != nil && h.readiness.IsDraining() {
+			report.Status = StatusDraining
+		}
+
+		status := http.StatusOK
+		if report.Status != StatusOk {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, report)
+	}
+}
+
+func runChecks(ctx context.Context, checks []*registeredCheck) HealthReport {
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check *registeredCheck) {
+			defer wg.Done()
+			results[i] = check.run(ctx)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := HealthReport{Status: StatusOk, Checks: results}
+	for _, result := range results {
+		if result.Status != StatusOk {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestHealth(t *testing.T) (*Health, *Readiness, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	readiness := NewReadiness()
+	health := NewHealth(readiness)
+	engine := gin.New()
+	health.Register(engine)
+	return health, readiness, engine
+}
+
+func probe(t *testing.T, handler http.Handler, req *http.Request) (int, HealthReport) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var report HealthReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body.String(), err)
+	}
+	return rec.Code, report
+}
+
+func TestHealthReportsFailingChecks(t *testing.T) {
+	health, _, engine := newTestHealth(t)
+	health.AddLivenessCheck(NewChecker("self", func(context.Context) error { return nil }))
+	health.AddReadinessCheck(NewChecker("db", func(context.Context) error { return errors.New("connection refused") }))
+
+	status, report := probe(t, engine, httptest.NewRequest(http.MethodGet, LivezPath, nil))
+	if status != http.StatusOK || report.Status != StatusOk || len(report.Checks) != 1 {
+		t.Errorf("livez: %d %+v", status, report)
+	}
+
+	status, report = probe(t, engine, httptest.NewRequest(http.MethodGet, ReadyzPath, nil))
+	if status != http.StatusServiceUnavailable || report.Status != StatusFail {
+		t.Errorf("readyz: %d %+v", status, report)
+	}
+	if len(report.Checks) != 1 || report.Checks[0].Error != "connection refused" {
+		t.Errorf("readyz checks: %+v", report.Checks)
+	}
+
+	status, report = probe(t, engine, httptest.NewRequest(http.MethodGet, HealthzPath, nil))
+	if status != http.StatusServiceUnavailable || len(report.Checks) != 2 {
+		t.Errorf("healthz: %d %+v", status, report)
+	}
+}
+
+func TestHealthReportsDraining(t *testing.T) {
+	_, readiness, engine := newTestHealth(t)
+	readiness.SetDraining()
+
+	status, report := probe(t, engine, httptest.NewRequest(http.MethodGet, ReadyzPath, nil))
+	if status != http.StatusServiceUnavailable || report.Status != StatusDraining {
+		t.Errorf("readyz: %d %+v", status, report)
+	}
+	if status, _ := probe(t, engine, httptest.NewRequest(http.MethodGet, LivezPath, nil)); status != http.StatusOK {
+		t.Errorf("livez: %d", status)
+	}
+}
+
+func TestHealthCachesResults(t *testing.T) {
+	health, _, engine := newTestHealth(t)
+	var calls atomic.Int32
+	health.AddReadinessCheck(NewChecker("db", func(context.Context) error {
+		calls.Add(1)
+		return nil
+	}), WithCheckCacheTTL(time.Minute))
+
+	_, first := probe(t, engine, httptest.NewRequest(http.MethodGet, ReadyzPath, nil))
+	_, second := probe(t, engine, httptest.NewRequest(http.MethodGet, ReadyzPath, nil))
+
+	if calls.Load() != 1 {
+		t.Errorf("check ran %d times", calls.Load())
+	}
+	if first.Checks[0].Cached || !second.Checks[0].Cached {
+		t.Errorf("cached flags: %v then %v", first.Checks[0].Cached, second.Checks[0].Cached)
+	}
+}
+
+func TestHealthCheckOutlivesProber(t *testing.T) {
+	health, _, engine := newTestHealth(t)
+	health.AddReadinessCheck(NewChecker("db", func(ctx context.Context) error {
+		select {
+		case <-time.After(20 * time.Millisecond):
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}), WithCheckCacheTTL(time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	probe(t, engine, httptest.NewRequest(http.MethodGet, ReadyzPath, nil).WithContext(ctx))
+
+	status, report := probe(t, engine, httptest.NewRequest(http.MethodGet, ReadyzPath, nil))
+	if status != http.StatusOK || !report.Checks[0].Cached {
+		t.Errorf("a prober hanging up was cached as a failure: %d %+v", status, report)
+	}
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	health, _, engine := newTestHealth(t)
+	health.AddReadinessCheck(NewChecker("stuck", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), WithCheckTimeout(10*time.Millisecond))
+
+	status, report := probe(t, engine, httptest.NewRequest(http.MethodGet, ReadyzPath, nil))
+	if status != http.StatusServiceUnavailable || report.Checks[0].Error != context.DeadlineExceeded.Error() {
+		t.Errorf("readyz: %d %+v", status, report)
+	}
+}
+
+func TestHealthEndpointsSkipAuthHooks(t *testing.T) {
+	deny := func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+	s := New(
+		WithGinMode(gin.TestMode),
+		WithLogger(discardLogger),
+		WithAuthHooks(deny),
+	)
+	s.Engine.GET("/private", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{HealthzPath, LivezPath, ReadyzPath, MetricsPath} {
+		rec := httptest.NewRecorder()
+		s.Engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: %d", path, rec.Code)
+		}
+	}
+	rec := httptest.NewRecorder()
+	s.Engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/private", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("/private: %d", rec.Code)
+	}
+}
+
+func TestHealthCheckPanicFailsTheCheck(t *testing.T) {
+	health, _, engine := newTestHealth(t)
+	health.AddLivenessCheck(NewChecker("buggy", func(context.Context) error {
+		panic("nil map")
+	}))
+
+	status, report := probe(t, engine, httptest.NewRequest(http.MethodGet, LivezPath, nil))
+	if status != http.StatusServiceUnavailable || report.Checks[0].Error != "panic: nil map" {
+		t.Errorf("livez: %d %+v", status, report)
+	}
+}
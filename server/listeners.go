@@ -0,0 +1,144 @@
+package server
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Listener is one of the http servers supervised by RunListeners, e.g. the
+// public API, an admin/metrics port or a gRPC gateway. Name identifies it in
+// errors. A server with a TLSConfig is served over TLS; its certificate must
+// come from TLSConfig (Certificates or GetCertificate). Socket, when set, is
+// served instead of binding Server.Addr, e.g. a socket from
+// InheritedListeners.
+type Listener struct {
+	Name   string
+	Server *http.Server
+	Socket net.Listener
+}
+
+// RunListeners binds and starts every listener concurrently and blocks until
+// the process receives SIGINT/SIGTERM or any listener fails, reloading on
+// SIGHUP meanwhile when WithReload is given. All listeners are then shut down
+// together, followed by the shutdown hooks. The returned
+// error is a *ShutdownError aggregating the listener failures and the
+// shutdown steps that failed, or nil.
+func RunListeners(listeners []Listener, opts ...TerminationOption) error {
+	o := newTerminationOptions(opts)
+
+	listeners, err := bind(listeners)
+	if err != nil {
+		return err
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sig)
+	stopReload := o.reloadOnHangup()
+	defer stopReload()
+
+	var handover chan os.Signal
+	if o.handoverLogger != nil {
+		handover = make(chan os.Signal, 1)
+		signal.Notify(handover, syscall.SIGUSR2)
+		defer signal.Stop(handover)
+	}
+
+	serveErrs := make(chan HookError, len(listeners))
+	for _, listener := range listeners {
+		go func(listener Listener) {
+			if err := listener.serve(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serveErrs <- HookError{Name: listener.Name, Err: err}
+			}
+		}(listener)
+	}
+
+	var failed []HookError
+wait:
+	for {
+		select {
+		case <-sig:
+			drain(sig, o)
+			break wait
+		case <-handover:
+			successor, err := StartSuccessor(listeners)
+			if err != nil {
+				o.handoverLogger.Error("socket handover failed, still serving", "error", err)
+				continue
+			}
+			// The successor accepts on the same sockets: flipping readiness
+			// here would fail about half of the load balancer probes and get
+			// the address taken out of rotation. Shutdown only closes this
+			// process's copy of the sockets.
+			o.handoverLogger.Info("sockets handed over, shutting down", "successor_pid", successor.Pid)
+			break wait
+		case serveErr := <-serveErrs:
+			// One listener is already gone, waiting for the load balancer
+			// buys nothing.
+			failed = append(failed, serveErr)
+			if o.readiness != nil {
+				o.readiness.SetDraining()
+			}
+			break wait
+		}
+	}
+
+	stopReload()
+	failed = append(failed, shutdown(listeners, o)...)
+	for {
+		select {
+		case serveErr := <-serveErrs:
+			failed = append(failed, serveErr)
+		default:
+			return toShutdownError(failed)
+		}
+	}
+}
+
+// bind opens the socket of every listener that does not have one yet, so that
+// address errors surface before anything is served.
+func bind(listeners []Listener) ([]Listener, error) {
+	bound := make([]Listener, len(listeners))
+	var failed []HookError
+	for i, listener := range listeners {
+		if listener.Socket == nil {
+			socket, err := net.Listen("tcp", listener.addr())
+			if err != nil {
+				failed = append(failed, HookError{Name: listener.Name, Err: err})
+			}
+			listener.Socket = socket
+		}
+		bound[i] = listener
+	}
+
+	if len(failed) > 0 {
+		for _, listener := range bound {
+			if listener.Socket != nil {
+				listener.Socket.Close() // #nosec G104
+			}
+		}
+		return nil, toShutdownError(failed)
+	}
+	return bound, nil
+}
+
+func (l Listener) addr() string {
+	if l.Server.Addr != "" {
+		return l.Server.Addr
+	}
+	if l.Server.TLSConfig != nil {
+		return ":https"
+	}
+	return ":http"
+}
+
+func (l Listener) serve() error {
+	if l.Server.TLSConfig != nil {
+		return l.Server.ServeTLS(l.Socket, "", "")
+	}
+	return l.Server.Serve(l.Socket)
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// testListener serves a handler answering name on a loopback socket.
+func testListener(t *testing.T, name string) Listener {
+	t.Helper()
+	socket, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Listener{
+		Name: name,
+		Server: &http.Server{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, name)
+			}),
+			ReadHeaderTimeout: time.Second,
+		},
+		Socket: socket,
+	}
+}
+
+func getBody(url string) (string, error) {
+	client := &http.Client{Timeout: time.Second}
+	defer client.CloseIdleConnections()
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return string(body), err
+}
+
+// runListeners runs RunListeners in the background and waits until every
+// listener answers.
+func runListeners(t *testing.T, listeners []Listener, opts ...TerminationOption) <-chan error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() {
+		done <- RunListeners(listeners, opts...)
+	}()
+	for _, listener := range listeners {
+		url := "http://" + listener.Socket.Addr().String()
+		deadline := time.Now().Add(time.Second)
+		for {
+			if body, err := getBody(url); err == nil && body == listener.Name {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("%s not served", listener.Name)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	return done
+}
+
+func waitDone(t *testing.T, done <-chan error) error {
+	t.Helper()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunListeners did not return")
+		return nil
+	}
+}
+
+func TestRunListenersServesEveryListener(t *testing.T) {
+	public, admin := testListener(t, "public"), testListener(t, "admin")
+	done := runListeners(t, []Listener{public, admin})
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	if err := waitDone(t, done); err != nil {
+		t.Fatal(err)
+	}
+	for _, listener := range []Listener{public, admin} {
+		if _, err := getBody("http://" + listener.Socket.Addr().String()); err == nil {
+			t.Errorf("%s still served after shutdown", listener.Name)
+		}
+	}
+}
+
+func TestRunListenersStopsAllWhenOneFails(t *testing.T) {
+	public, admin := testListener(t, "public"), testListener(t, "admin")
+	readiness := NewReadiness()
+	var hookRan bool
+	hook := ShutdownHook{Name: "flush", Fn: func(context.Context) error {
+		hookRan = true
+		return nil
+	}}
+	done := runListeners(t, []Listener{public, admin}, WithDrain(readiness, time.Hour), WithShutdownHooks(hook))
+
+	// Closing the socket under the admin server makes its Serve fail.
+	admin.Socket.Close() // #nosec G104
+	err := waitDone(t, done)
+
+	var shutdownErr *ShutdownError
+	if !errors.As(err, &shutdownErr) || len(shutdownErr.Failed) != 1 || shutdownErr.Failed[0].Name != "admin" {
+		t.Fatalf("err = %v", err)
+	}
+	if !readiness.IsDraining() {
+		t.Error("readiness still up")
+	}
+	if !hookRan {
+		t.Error("shutdown hooks skipped")
+	}
+	if _, err := getBody("http://" + public.Socket.Addr().String()); err == nil {
+		t.Error("public listener still served")
+	}
+}
+
+func TestRunListenersShutdownOrder(t *testing.T) {
+	public := testListener(t, "public")
+	readiness := NewReadiness()
+	var mu sync.Mutex
+	var steps []string
+	step := func(s string) {
+		mu.Lock()
+		defer mu.Unlock()
+		steps = append(steps, s)
+	}
+	hook := func(name string) ShutdownHook {
+		return ShutdownHook{Name: name, Fn: func(context.Context) error {
+			if _, err := getBody("http://" + public.Socket.Addr().String()); err == nil {
+				step(name + " while serving")
+			}
+			step(name)
+			return nil
+		}}
+	}
+	done := runListeners(t, []Listener{public}, WithDrain(readiness, 50*time.Millisecond), WithShutdownHooks(hook("flush"), hook("close")))
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	// During the drain delay readiness is down but requests are served.
+	deadline := time.Now().Add(time.Second)
+	for !readiness.IsDraining() {
+		if time.Now().After(deadline) {
+			t.Fatal("readiness not drained")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if body, err := getBody("http://" + public.Socket.Addr().String()); err != nil || body != "public" {
+		t.Errorf("not served while draining: %q, %v", body, err)
+	}
+
+	if err := waitDone(t, done); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(steps, ","); got != "flush,close" {
+		t.Errorf("steps = %s", got)
+	}
+}
+
+func TestBindReportsEveryAddressError(t *testing.T) {
+	taken := testListener(t, "taken")
+	defer taken.Socket.Close()
+	free := Listener{Name: "free", Server: &http.Server{Addr: "127.0.0.1:0"}}
+	clash := Listener{Name: "clash", Server: &http.Server{Addr: taken.Socket.Addr().String()}}
+
+	_, err := bind([]Listener{free, clash})
+	var shutdownErr *ShutdownError
+	if !errors.As(err, &shutdownErr) || len(shutdownErr.Failed) != 1 || shutdownErr.Failed[0].Name != "clash" {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+type options struct {
+	addr              string
+	readTimeout       time.Duration
+	readHeaderTimeout time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+	ginMode           string
+	logger            *slog.Logger
+	authHooks         []gin.HandlerFunc
+	tracing           []middleware.TracingOption
+	metricsAddr       string
+	metricsDisabled   bool
+	requestTimeout    time.Duration
+	middlewares       []gin.HandlerFunc
+	shutdownTimeout   time.Duration
+	shutdownHooks     []ShutdownHook
+	drainDelay        time.Duration
+	reloadTimeout     time.Duration
+	tls               *TLSConfig
+	socketActivation  bool
+	handover          bool
+}
+
+func defaultOptions() options {
+	return options{
+		addr:              ":8080",
+		readTimeout:       15 * time.Second,
+		readHeaderTimeout: 5 * time.Second,
+		writeTimeout:      30 * time.Second,
+		idleTimeout:       60 * time.Second,
+		ginMode:           gin.ReleaseMode,
+		logger:            slog.Default(),
+		shutdownTimeout:   DefaultShutdownTimeout,
+	}
+}
+
+type Option func(*options)
+
+// WithAddr sets the TCP address the server listens on. Defaults to ":8080".
+func WithAddr(addr string) Option {
+	return func(o *options) {
+		o.addr = addr
+	}
+}
+
+// WithTimeouts sets the read, write and idle timeouts of the http.Server.
+// A zero value keeps the default.
+func WithTimeouts(read time.Duration, write time.Duration, idle time.Duration) Option {
+	return func(o *options) {
+		if read > 0 {
+			o.readTimeout = read
+		}
+		if write > 0 {
+			o.writeTimeout = write
+		}
+		if idle > 0 {
+			o.idleTimeout = idle
+		}
+	}
+}
+
+// WithReadHeaderTimeout sets the time allowed to read the request headers.
+func WithReadHeaderTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.readHeaderTimeout = timeout
+	}
+}
+
+// WithGinMode sets the gin mode (gin.DebugMode, gin.ReleaseMode or gin.TestMode).
+func WithGinMode(mode string) Option {
+	return func(o *options) {
+		o.ginMode = mode
+	}
+}
+
+// WithLogger sets the logger the server and its middleware log to. Defaults
+// to slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithAuthHooks installs authentication/authorisation middleware right after
+// the standard middleware, typically built with routeutils.HandleMiddleware.
+func WithAuthHooks(hooks ...gin.HandlerFunc) Option {
+	return func(o *options) {
+		o.authHooks = append(o.authHooks, hooks...)
+	}
+}
+
+// WithTracing configures the tracing middleware, e.g. with the tracer
+// provider of the application instead of the global one.
+func WithTracing(opts ...middleware.TracingOption) Option {
+	return func(o *options) {
+		o.tracing = append(o.tracing, opts...)
+	}
+}
+
+// WithMetricsAddr serves MetricsPath on a separate listener bound to addr,
+// e.g. an admin port not exposed publicly, instead of on the main server.
+func WithMetricsAddr(addr string) Option {
+	return func(o *options) {
+		o.metricsAddr = addr
+	}
+}
+
+// WithoutMetricsEndpoint does not serve MetricsPath at all. The metrics are
+// still collected in metrics.Registry, for the application to expose.
+func WithoutMetricsEndpoint() Option {
+	return func(o *options) {
+		o.metricsDisabled = true
+	}
+}
+
+// WithRequestTimeout sets a deadline on every request, see
+// middleware.Timeout. Zero, the default, leaves requests without deadline.
+//
+// With a deadline, handlers run on a copy of the gin context whose response
+// is buffered until they return: streaming responses are not flushed early,
+// connections cannot be hijacked (websockets) and CloseNotify never fires.
+// Keys set and errors recorded on the copy reach the original context only
+// when the handler returns in time.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.requestTimeout = timeout
+	}
+}
+
+// WithMiddleware installs additional middleware after the auth hooks.
+func WithMiddleware(middlewares ...gin.HandlerFunc) Option {
+	return func(o *options) {
+		o.middlewares = append(o.middlewares, middlewares...)
+	}
+}
+
+// WithShutdownTimeout sets the grace period for in-flight requests on
+// shutdown. Shutdown hooks have their own budget, see ShutdownHook.
+func WithShutdownTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.shutdownTimeout = timeout
+	}
+}
+
+// WithShutdownHook registers a hook run after the http server has stopped.
+func WithShutdownHook(hook ShutdownHook) Option {
+	return func(o *options) {
+		o.shutdownHooks = append(o.shutdownHooks, hook)
+	}
+}
+
+// WithDrainDelay sets how long /readyz reports 503 before the server stops
+// accepting connections. Defaults to no delay.
+func WithDrainDelay(delay time.Duration) Option {
+	return func(o *options) {
+		o.drainDelay = delay
+	}
+}
+
+// WithReloadTimeout bounds the reload of each component registered with
+// Server.OnReload. Defaults to DefaultReloadTimeout.
+func WithReloadTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.reloadTimeout = timeout
+	}
+}
+
+// WithTLS serves the main listener over TLS, or mutual TLS when
+// cfg.ClientCAFile is set.
+func WithTLS(cfg TLSConfig) Option {
+	return func(o *options) {
+		o.tls = &cfg
+	}
+}
+
+// WithSocketActivation serves the sockets passed by systemd (LISTEN_FDS) or
+// by a predecessor process instead of binding the addresses. Sockets are
+// matched to listeners by name; a single unnamed socket goes to the main
+// server. Listeners without a matching socket bind as usual.
+func WithSocketActivation() Option {
+	return func(o *options) {
+		o.socketActivation = true
+	}
+}
+
+// WithHandover hands the sockets over to a new copy of the binary on SIGUSR2
+// and shuts this one down, see WithSocketHandover. Combine with
+// WithSocketActivation so the new process picks the sockets up.
+func WithHandover() Option {
+	return func(o *options) {
+		o.handover = true
+	}
+}
@@ -0,0 +1,26 @@
+package server
+
+import "sync/atomic"
+
+// Readiness tracks whether the process should still receive traffic. It
+// flips to draining when shutdown starts so load balancers stop routing to
+// the instance before the listener closes.
+type Readiness struct {
+	draining atomic.Bool
+}
+
+// NewReadiness returns a Readiness that is not draining.
+func NewReadiness() *Readiness {
+	return &Readiness{}
+}
+
+// SetDraining marks the process as draining. It cannot be undone: a process
+// shutting down does not come back.
+func (r *Readiness) SetDraining() {
+	r.draining.Store(true)
+}
+
+// IsDraining reports whether SetDraining has been called.
+func (r *Readiness) IsDraining() bool {
+	return r.draining.Load()
+}
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestReadyzReportsDrainingDuringTheDrainDelay(t *testing.T) {
+	s := New(WithGinMode(gin.TestMode), WithLogger(discardLogger))
+	readyz := func() int {
+		rec := httptest.NewRecorder()
+		s.Engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReadyzPath, nil))
+		return rec.Code
+	}
+	done := WaitForTermination(&http.Server{}, WithDrain(s.Readiness(), time.Hour))
+
+	if status := readyz(); status != http.StatusOK {
+		t.Fatalf("readyz before termination: %d", status)
+	}
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(time.Second)
+	for readyz() != http.StatusServiceUnavailable {
+		if time.Now().After(deadline) {
+			t.Fatal("readyz still up during the drain delay")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	select {
+	case <-done:
+		t.Fatal("shut down before the drain delay")
+	default:
+	}
+
+	// A second signal cuts the delay short.
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("second signal ignored")
+	}
+}
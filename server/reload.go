@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// DefaultReloadTimeout bounds the reload of a single component.
+const DefaultReloadTimeout = 30 * time.Second
+
+// Reloadable is a component that can pick up new configuration while the
+// server keeps serving, e.g. log level, rate limits, TLS certificates or
+// feature flags.
+type Reloadable interface {
+	Name() string
+	Reload(ctx context.Context) error
+}
+
+type reloadableFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (r reloadableFunc) Name() string {
+	return r.name
+}
+
+func (r reloadableFunc) Reload(ctx context.Context) error {
+	return r.fn(ctx)
+}
+
+func NewReloadable(name string, fn func(ctx context.Context) error) Reloadable {
+	return reloadableFunc{name: name, fn: fn}
+}
+
+// ReloadOnHangup reloads every component, in order, each time the process
+// receives SIGHUP, until ctx is done. SIGHUP is caught from the moment it
+// returns, so it no longer terminates the process; the reloads run in the
+// background. Listeners are left untouched so no connection is dropped. Each
+// component gets timeout (DefaultReloadTimeout when zero) and the outcome of
+// each reload is logged.
+func ReloadOnHangup(ctx context.Context, logger *slog.Logger, timeout time.Duration, components ...Reloadable) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(sig)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sig:
+				logger.Info("reload requested", "components", len(components))
+				ReloadAll(ctx, logger, timeout, components...)
+			}
+		}
+	}()
+}
+
+// ReloadAll reloads every component in order, each within timeout
+// (DefaultReloadTimeout when zero), logging success or failure for each, and
+// reports how many failed. A component that overruns its timeout is left
+// running and counted as failed.
+func ReloadAll(ctx context.Context, logger *slog.Logger, timeout time.Duration, components ...Reloadable) int {
+	if timeout <= 0 {
+		timeout = DefaultReloadTimeout
+	}
+	failed := 0
+	for _, component := range components {
+		if err := reload(ctx, component, timeout); err != nil {
+			failed++
+			logger.Error("reload failed", "component", component.Name(), "error", err)
+			continue
+		}
+		logger.Info("reloaded", "component", component.Name())
+	}
+	return failed
+}
+
+func reload(ctx context.Context, component Reloadable, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if exception := recover(); exception != nil {
+				result <- fmt.Errorf("panic: %+v", exception)
+			}
+		}()
+		result <- component.Reload(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
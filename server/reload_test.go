@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestReloadAllBoundsEachComponent(t *testing.T) {
+	var reloaded []string
+	components := []Reloadable{
+		NewReloadable("stuck", func(ctx context.Context) error {
+			<-ctx.Done()
+			time.Sleep(time.Second)
+			return nil
+		}),
+		NewReloadable("panics", func(context.Context) error {
+			panic("boom")
+		}),
+		NewReloadable("fails", func(context.Context) error {
+			return errors.New("bad config")
+		}),
+		NewReloadable("ok", func(context.Context) error {
+			reloaded = append(reloaded, "ok")
+			return nil
+		}),
+	}
+
+	start := time.Now()
+	failed := ReloadAll(context.Background(), discardLogger, 10*time.Millisecond, components...)
+	if failed != 3 {
+		t.Errorf("%d failed, want 3", failed)
+	}
+	if len(reloaded) != 1 {
+		t.Errorf("components after the failing ones were not reloaded: %v", reloaded)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("a stuck component held the reload for %s", elapsed)
+	}
+}
+
+func TestReloadOnHangupCatchesSignalOnReturn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reloaded := make(chan struct{}, 1)
+	ReloadOnHangup(ctx, discardLogger, 0, NewReloadable("config", func(context.Context) error {
+		reloaded <- struct{}{}
+		return nil
+	}))
+
+	// Sent right away: without the handler in place SIGHUP would kill the
+	// test binary.
+	if err := syscall.Kill(os.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Fatal("not reloaded")
+	}
+}
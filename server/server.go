@@ -2,32 +2,222 @@ package server
 
 import (
 	"context"
-	"fmt"
 	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
 	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/errors"
+	"github.com/PrathamSkilltelligent/pmgingo/metrics"
+	"github.com/PrathamSkilltelligent/pmgingo/middleware"
+	"github.com/gin-gonic/gin"
 )
 
-func WaitForTermination(server *http.Server) <-chan struct{} {
-	sig := make(chan os.Signal, 1)
-	done := make(chan struct{})
+// MetricsPath serves the metrics of metrics.Registry.
+const MetricsPath = "/metrics"
+
+// Server bundles the gin engine with the http.Server serving it. Routes are
+// registered on Engine before calling Run.
+type Server struct {
+	Engine *gin.Engine
+
+	httpServer  *http.Server
+	readiness   *Readiness
+	health      *Health
+	listeners   []Listener
+	reloadables []Reloadable
+	opts        options
+}
+
+// New builds a gin engine with the standard middleware chain (recovery,
+// tracing, call id, request logger, metrics, access log, request timeout,
+// auth hooks, custom middleware) and the http.Server that will serve it.
+// The health endpoints are registered on HealthzPath, LivezPath and
+// ReadyzPath, and the metrics on MetricsPath, ahead of the request timeout,
+// auth hooks and custom middleware so probes and scrapes need no
+// credentials. See WithMetricsAddr and WithoutMetricsEndpoint to serve the
+// metrics elsewhere or not at all.
+//
+// New also runs errors.CheckConstructors against errors.DefaultRegistry and
+// logs an error when an application redefined a library code so that its
+// constructor no longer matches.
+func New(opts ...Option) *Server {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if err := errors.CheckConstructors(errors.DefaultRegistry); err != nil {
+		o.logger.Error("fault self-check failed", "error", err)
+	}
+
+	gin.SetMode(o.ginMode)
+	engine := gin.New()
+	engine.Use(
+		middleware.Recovery(o.logger),
+		middleware.Tracing(o.tracing...),
+		middleware.CallId(),
+		middleware.ContextLogger(o.logger),
+		middleware.Metrics(),
+		middleware.AccessLog(o.logger),
+	)
+
+	// Probes carry no credentials: the health endpoints are registered
+	// before the auth hooks and custom middleware so those do not apply.
+	readiness := NewReadiness()
+	health := NewHealth(readiness)
+	health.Register(engine)
 
-	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	var listeners []Listener
+	switch {
+	case o.metricsDisabled:
+	case o.metricsAddr != "":
+		mux := http.NewServeMux()
+		mux.Handle(MetricsPath, metrics.Handler())
+		listeners = append(listeners, Listener{Name: "metrics", Server: &http.Server{
+			Addr:              o.metricsAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: o.readHeaderTimeout,
+		}})
+	default:
+		engine.GET(MetricsPath, gin.WrapH(metrics.Handler()))
+	}
 
-	go func() {
-		<-sig
+	if o.requestTimeout > 0 {
+		engine.Use(middleware.Timeout(o.requestTimeout))
+	}
+	engine.Use(o.authHooks...)
+	engine.Use(o.middlewares...)
 
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer shutdownCancel()
+	return &Server{
+		Engine: engine,
+		httpServer: &http.Server{
+			Addr:              o.addr,
+			Handler:           engine,
+			ReadTimeout:       o.readTimeout,
+			ReadHeaderTimeout: o.readHeaderTimeout,
+			WriteTimeout:      o.writeTimeout,
+			IdleTimeout:       o.idleTimeout,
+		},
+		readiness: readiness,
+		health:    health,
+		listeners: listeners,
+		opts:      o,
+	}
+}
+
+// HttpServer returns the underlying http.Server.
+func (s *Server) HttpServer() *http.Server {
+	return s.httpServer
+}
+
+// Readiness returns the readiness reported by ReadyzPath. Run sets it
+// draining on SIGINT/SIGTERM; applications may set it draining earlier, e.g.
+// when a dependency they cannot serve without is gone for good.
+func (s *Server) Readiness() *Readiness {
+	return s.readiness
+}
+
+// Health returns the registry backing the health endpoints, for adding
+// dependency checks.
+func (s *Server) Health() *Health {
+	return s.health
+}
 
-		if err := server.Shutdown(shutdownCtx); err != nil {
-			fmt.Println("Failed to shutdown server", err)
+// OnShutdown registers a hook run after the http server has stopped. Hooks
+// run in registration order; see ShutdownHook.
+func (s *Server) OnShutdown(name string, timeout time.Duration, fn func(ctx context.Context) error) {
+	s.opts.shutdownHooks = append(s.opts.shutdownHooks, ShutdownHook{
+		Name:    name,
+		Timeout: timeout,
+		Fn:      fn,
+	})
+}
+
+// OnReload registers a component reloaded, in registration order, when the
+// process receives SIGHUP.
+func (s *Server) OnReload(name string, fn func(ctx context.Context) error) {
+	s.reloadables = append(s.reloadables, NewReloadable(name, fn))
+}
+
+// AddListener supervises an additional http server, such as an admin or
+// metrics port, alongside the main one. It is started and shut down together
+// with the main server by Run.
+func (s *Server) AddListener(name string, server *http.Server) {
+	s.listeners = append(s.listeners, Listener{Name: name, Server: server})
+}
+
+// Run starts serving on every listener and blocks until the process receives
+// SIGINT/SIGTERM or a listener fails, and the shutdown sequence has
+// completed. The returned error is a *ShutdownError when a listener or any
+// shutdown step failed. Meanwhile SIGHUP reloads the components registered
+// with OnReload, and the TLS certificate when serving TLS.
+func (s *Server) Run() error {
+	runCtx, stopRun := context.WithCancel(context.Background())
+	defer stopRun()
+
+	reloadables := append([]Reloadable{}, s.reloadables...)
+	if s.opts.tls != nil {
+		tlsConfig, reloader, err := s.opts.tls.Build()
+		if err != nil {
+			return err
 		}
+		s.httpServer.TLSConfig = tlsConfig
+		reloadables = append(reloadables, NewReloadable("tls-certificate", func(context.Context) error {
+			return reloader.Reload()
+		}))
 
-		close(done)
-	}()
+		if interval := s.opts.tls.reloadInterval(); interval > 0 {
+			go reloader.Watch(runCtx, interval, s.opts.logger)
+		}
+	}
+
+	listeners := append([]Listener{{Name: "http-server", Server: s.httpServer}}, s.listeners...)
+	if s.opts.socketActivation {
+		if err := s.attachInheritedSockets(listeners); err != nil {
+			return err
+		}
+	}
+	for _, listener := range listeners {
+		if listener.Socket != nil {
+			s.opts.logger.Info("server listening", "name", listener.Name, "addr", listener.Socket.Addr().String(), "inherited", true)
+		} else {
+			s.opts.logger.Info("server listening", "name", listener.Name, "addr", listener.Server.Addr)
+		}
+	}
 
-	return done
+	terminationOpts := []TerminationOption{
+		WithGracePeriod(s.opts.shutdownTimeout),
+		WithShutdownHooks(s.opts.shutdownHooks...),
+		WithDrain(s.readiness, s.opts.drainDelay),
+		WithReload(s.opts.logger, s.opts.reloadTimeout, reloadables...),
+	}
+	if s.opts.handover {
+		terminationOpts = append(terminationOpts, WithSocketHandover(s.opts.logger))
+	}
+	err := RunListeners(listeners, terminationOpts...)
+	if err != nil {
+		s.opts.logger.Error("server run failed", "error", err)
+	}
+	return err
+}
+
+func (s *Server) attachInheritedSockets(listeners []Listener) error {
+	inherited, err := InheritedListeners()
+	if err != nil {
+		return err
+	}
+	if socket, ok := inherited[UnnamedSocket]; ok && len(inherited) == 1 {
+		listeners[0].Socket = socket
+		return nil
+	}
+	for i := range listeners {
+		if socket, ok := inherited[listeners[i].Name]; ok {
+			listeners[i].Socket = socket
+			delete(inherited, listeners[i].Name)
+		}
+	}
+	for name, socket := range inherited {
+		s.opts.logger.Warn("inherited socket matches no listener, closing it", "name", name)
+		socket.Close() // #nosec G104
+	}
+	return nil
 }
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/middleware"
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/gin-gonic/gin"
+)
+
+var discardLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestNewMiddlewareOrder(t *testing.T) {
+	var steps []string
+	record := func(step string) gin.HandlerFunc {
+		return func(c *gin.Context) {
+			steps = append(steps, step)
+		}
+	}
+	s := New(
+		WithGinMode(gin.TestMode),
+		WithLogger(discardLogger),
+		WithRequestTimeout(time.Minute),
+		WithAuthHooks(record("auth")),
+		WithMiddleware(record("custom")),
+	)
+	s.Engine.GET("/orders", func(c *gin.Context) {
+		if _, ok := c.Get(routeutils.CallIdKey); ok {
+			steps = append(steps, "call id")
+		}
+		if _, ok := c.Request.Context().Deadline(); ok {
+			steps = append(steps, "deadline")
+		}
+		steps = append(steps, "handler")
+	})
+	s.Engine.GET("/panics", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	s.Engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/orders", nil))
+	if got := strings.Join(steps, ","); got != "auth,custom,call id,deadline,handler" {
+		t.Errorf("steps = %s", got)
+	}
+	if rec.Header().Get(middleware.CallIdHeader) == "" {
+		t.Error("no call id header")
+	}
+
+	rec = httptest.NewRecorder()
+	s.Engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panics", nil))
+	if rec.Code != http.StatusInternalServerError || rec.Header().Get(middleware.CallIdHeader) == "" {
+		t.Errorf("panic answered %d with headers %v", rec.Code, rec.Header())
+	}
+	// Probes and scrapes are served ahead of the timeout, auth hooks and
+	// custom middleware.
+	for _, path := range []string{HealthzPath, LivezPath, ReadyzPath, MetricsPath} {
+		steps = nil
+		rec := httptest.NewRecorder()
+		s.Engine.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || len(steps) > 0 {
+			t.Errorf("%s: %d after %v", path, rec.Code, steps)
+		}
+	}
+}
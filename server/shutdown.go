@@ -0,0 +1,258 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultShutdownTimeout = 10 * time.Second
+	DefaultHookTimeout     = 5 * time.Second
+)
+
+// ShutdownHook is run once the http server has stopped accepting requests.
+// Hooks run one after the other in registration order, each with its own
+// budget of Timeout (DefaultHookTimeout when zero) that starts when the hook
+// does. The grace period of the http server does not apply to hooks, so a
+// slow connection drain cannot starve them.
+type ShutdownHook struct {
+	Name    string
+	Timeout time.Duration
+	Fn      func(ctx context.Context) error
+}
+
+// HookError names the step that failed: a listener or a shutdown hook.
+type HookError struct {
+	Name string
+	Err  error
+}
+
+func (e HookError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Name, e.Err)
+}
+
+func (e HookError) Unwrap() error {
+	return e.Err
+}
+
+// ShutdownError lists every listener failure and every step of the shutdown
+// sequence that failed.
+type ShutdownError struct {
+	Failed []HookError
+}
+
+func (e *ShutdownError) Error() string {
+	msgs := make([]string, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		msgs = append(msgs, f.Error())
+	}
+	return "server stopped with errors: " + strings.Join(msgs, "; ")
+}
+
+func (e *ShutdownError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, f := range e.Failed {
+		errs = append(errs, f)
+	}
+	return errs
+}
+
+type terminationOptions struct {
+	gracePeriod    time.Duration
+	hooks          []ShutdownHook
+	readiness      *Readiness
+	drainDelay     time.Duration
+	handoverLogger *slog.Logger
+	reloadLogger   *slog.Logger
+	reloadTimeout  time.Duration
+	reloadables    []Reloadable
+}
+
+type TerminationOption func(*terminationOptions)
+
+// WithGracePeriod bounds the shutdown of the http servers, i.e. waiting for
+// in-flight requests. The hooks that follow have their own budget, see
+// ShutdownHook. Defaults to DefaultShutdownTimeout.
+func WithGracePeriod(d time.Duration) TerminationOption {
+	return func(o *terminationOptions) {
+		o.gracePeriod = d
+	}
+}
+
+// WithDrain marks readiness as draining as soon as a termination signal
+// arrives and waits delay before shutting the server down, giving load
+// balancers time to take the instance out of rotation. A second signal
+// during the delay skips the rest of the wait.
+func WithDrain(readiness *Readiness, delay time.Duration) TerminationOption {
+	return func(o *terminationOptions) {
+		o.readiness = readiness
+		o.drainDelay = delay
+	}
+}
+
+// WithSocketHandover makes RunListeners hand its sockets over to a freshly started
+// copy of the binary on SIGUSR2 (see StartSuccessor) and then shut down, for
+// restarts that do not drop connections. There is no drain phase: readiness
+// stays up since the successor serves the same sockets. Handover failures
+// are logged and the process keeps serving.
+func WithSocketHandover(logger *slog.Logger) TerminationOption {
+	return func(o *terminationOptions) {
+		o.handoverLogger = logger
+	}
+}
+
+// WithReload reloads components on SIGHUP until shutdown starts, see
+// ReloadOnHangup. SIGHUP is caught from the moment RunListeners or
+// WaitForTermination is called.
+func WithReload(logger *slog.Logger, timeout time.Duration, components ...Reloadable) TerminationOption {
+	return func(o *terminationOptions) {
+		o.reloadLogger = logger
+		o.reloadTimeout = timeout
+		o.reloadables = append(o.reloadables, components...)
+	}
+}
+
+// WithShutdownHooks adds hooks run, in order, after the http servers have
+// shut down.
+func WithShutdownHooks(hooks ...ShutdownHook) TerminationOption {
+	return func(o *terminationOptions) {
+		o.hooks = append(o.hooks, hooks...)
+	}
+}
+
+// WaitForTermination shuts the server down on SIGINT/SIGTERM, after the drain
+// phase when one is configured, and then runs the shutdown hooks. The
+// returned channel yields nil or a *ShutdownError once everything has
+// completed, and is then closed.
+//
+// The channel used to be a <-chan struct{} that was only closed; callers
+// that declared its type need to switch to <-chan error.
+func WaitForTermination(server *http.Server, opts ...TerminationOption) <-chan error {
+	o := newTerminationOptions(opts)
+
+	sig := make(chan os.Signal, 1)
+	done := make(chan error, 1)
+
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	stopReload := o.reloadOnHangup()
+
+	go func() {
+		<-sig
+		stopReload()
+		drain(sig, o)
+		signal.Stop(sig)
+
+		done <- toShutdownError(shutdown([]Listener{{Name: "http-server", Server: server}}, o))
+		close(done)
+	}()
+
+	return done
+}
+
+func newTerminationOptions(opts []TerminationOption) terminationOptions {
+	o := terminationOptions{gracePeriod: DefaultShutdownTimeout}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// reloadOnHangup starts reloading on SIGHUP when WithReload was given. The
+// returned function stops it.
+func (o terminationOptions) reloadOnHangup() context.CancelFunc {
+	ctx, cancel := context.WithCancel(context.Background())
+	if o.reloadLogger != nil {
+		ReloadOnHangup(ctx, o.reloadLogger, o.reloadTimeout, o.reloadables...)
+	}
+	return cancel
+}
+
+func drain(sig <-chan os.Signal, o terminationOptions) {
+	if o.readiness == nil {
+		return
+	}
+	o.readiness.SetDraining()
+	if o.drainDelay <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(o.drainDelay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-sig:
+	}
+}
+
+// shutdown stops all listeners concurrently within the grace period, then
+// runs the hooks in order.
+func shutdown(listeners []Listener, o terminationOptions) []HookError {
+	graceCtx, graceCancel := context.WithTimeout(context.Background(), o.gracePeriod)
+	defer graceCancel()
+
+	listenerErrs := make([]error, len(listeners))
+	var wg sync.WaitGroup
+	for i, listener := range listeners {
+		wg.Add(1)
+		go func(i int, listener Listener) {
+			defer wg.Done()
+			listenerErrs[i] = listener.Server.Shutdown(graceCtx)
+		}(i, listener)
+	}
+	wg.Wait()
+
+	var failed []HookError
+	for i, err := range listenerErrs {
+		if err != nil {
+			failed = append(failed, HookError{Name: listeners[i].Name, Err: err})
+		}
+	}
+	for _, hook := range o.hooks {
+		if err := runHook(hook); err != nil {
+			failed = append(failed, HookError{Name: hook.Name, Err: err})
+		}
+	}
+	return failed
+}
+
+func toShutdownError(failed []HookError) error {
+	if len(failed) > 0 {
+		return &ShutdownError{Failed: failed}
+	}
+	return nil
+}
+
+func runHook(hook ShutdownHook) error {
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result := make(chan error, 1)
+	go func() {
+		defer func() {
+			if exception := recover(); exception != nil {
+				result <- fmt.Errorf("panic: %+v", exception)
+			}
+		}()
+		result <- hook.Fn(ctx)
+	}()
+
+	// A hook that ignores its context must not hold up the rest of the sequence.
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
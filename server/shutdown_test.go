@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShutdownHooksHaveTheirOwnBudget(t *testing.T) {
+	var ran []string
+	o := newTerminationOptions([]TerminationOption{
+		// The grace period is already over when the hooks start.
+		WithGracePeriod(time.Nanosecond),
+		WithShutdownHooks(
+			ShutdownHook{Name: "flush", Timeout: time.Second, Fn: func(ctx context.Context) error {
+				time.Sleep(10 * time.Millisecond)
+				ran = append(ran, "flush")
+				return ctx.Err()
+			}},
+			ShutdownHook{Name: "close", Fn: func(ctx context.Context) error {
+				ran = append(ran, "close")
+				return ctx.Err()
+			}},
+		),
+	})
+
+	if failed := shutdown([]Listener{{Name: "http-server", Server: &http.Server{}}}, o); len(failed) > 0 {
+		t.Fatalf("failed: %v", failed)
+	}
+	if strings.Join(ran, ",") != "flush,close" {
+		t.Errorf("hooks ran: %v", ran)
+	}
+}
+
+func TestShutdownReportsFailingHooks(t *testing.T) {
+	o := newTerminationOptions([]TerminationOption{
+		WithShutdownHooks(
+			ShutdownHook{Name: "stuck", Timeout: 10 * time.Millisecond, Fn: func(context.Context) error {
+				select {}
+			}},
+			ShutdownHook{Name: "panics", Fn: func(context.Context) error {
+				panic("boom")
+			}},
+			ShutdownHook{Name: "ok", Fn: func(context.Context) error {
+				return nil
+			}},
+		),
+	})
+
+	err := toShutdownError(shutdown(nil, o))
+	var shutdownErr *ShutdownError
+	if !errors.As(err, &shutdownErr) {
+		t.Fatalf("err = %v", err)
+	}
+	if len(shutdownErr.Failed) != 2 {
+		t.Fatalf("failed: %v", shutdownErr.Failed)
+	}
+	if shutdownErr.Failed[0].Name != "stuck" || !errors.Is(shutdownErr.Failed[0].Err, context.DeadlineExceeded) {
+		t.Errorf("stuck: %v", shutdownErr.Failed[0])
+	}
+	if shutdownErr.Failed[1].Name != "panics" {
+		t.Errorf("panics: %v", shutdownErr.Failed[1])
+	}
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// Environment of the socket activation protocol. systemd sets LISTEN_PID to
+// the activated process; a predecessor handing its sockets over sets
+// LISTEN_PARENT_PID to its own pid instead, since it cannot know the pid of
+// the process it starts.
+const (
+	ListenPidEnv       = "LISTEN_PID"
+	ListenParentPidEnv = "LISTEN_PARENT_PID"
+	ListenFdsEnv       = "LISTEN_FDS"
+	ListenFdNamesEnv   = "LISTEN_FDNAMES"
+
+	// UnnamedSocket is the name systemd gives sockets without
+	// FileDescriptorName=.
+	UnnamedSocket = "unknown"
+
+	listenFdsStart = 3
+)
+
+// InheritedListeners returns the sockets passed to this process by systemd
+// socket activation or by a predecessor via StartSuccessor, keyed by name.
+// It returns an empty map when nothing was passed, and fails when
+// LISTEN_FDNAMES does not name exactly LISTEN_FDS sockets. The environment
+// variables are cleared so that child processes do not inherit them.
+func InheritedListeners() (map[string]net.Listener, error) {
+	listeners := map[string]net.Listener{}
+	if !socketsAddressedToUs() {
+		return listeners, nil
+	}
+	defer unsetListenEnv()
+
+	count, err := strconv.Atoi(os.Getenv(ListenFdsEnv))
+	if err != nil || count < 0 {
+		return nil, fmt.Errorf("invalid %s %q", ListenFdsEnv, os.Getenv(ListenFdsEnv))
+	}
+	var names []string
+	if fdNames := os.Getenv(ListenFdNamesEnv); fdNames != "" {
+		names = strings.Split(fdNames, ":")
+		if len(names) != count {
+			return nil, fmt.Errorf("%s names %d sockets but %s is %d", ListenFdNamesEnv, len(names), ListenFdsEnv, count)
+		}
+	}
+
+	for i := 0; i < count; i++ {
+		fd := listenFdsStart + i
+		syscall.CloseOnExec(fd)
+
+		name := UnnamedSocket
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		if _, exists := listeners[name]; exists {
+			closeAll(listeners)
+			return nil, fmt.Errorf("inherited socket name %q is used twice, set distinct names", name)
+		}
+
+		file := os.NewFile(uintptr(fd), name)
+		listener, err := net.FileListener(file)
+		file.Close() // #nosec G104 -- FileListener holds its own dup
+		if err != nil {
+			closeAll(listeners)
+			return nil, fmt.Errorf("inherited socket %s (fd %d): %w", name, fd, err)
+		}
+		listeners[name] = listener
+	}
+	return listeners, nil
+}
+
+func socketsAddressedToUs() bool {
+	if pid := os.Getenv(ListenPidEnv); pid != "" {
+		return pid == strconv.Itoa(os.Getpid())
+	}
+	if ppid := os.Getenv(ListenParentPidEnv); ppid != "" {
+		return ppid == strconv.Itoa(os.Getppid())
+	}
+	return false
+}
+
+func unsetListenEnv() {
+	for _, env := range []string{ListenPidEnv, ListenParentPidEnv, ListenFdsEnv, ListenFdNamesEnv} {
+		os.Unsetenv(env) // #nosec G104
+	}
+}
+
+func closeAll(listeners map[string]net.Listener) {
+	for _, listener := range listeners {
+		listener.Close() // #nosec G104
+	}
+}
+
+// StartSuccessor re-executes the running binary with the same arguments and
+// passes it the listeners' sockets, so it can pick them up through
+// InheritedListeners while this process drains. Every listener must already
+// be bound, i.e. have a Socket.
+func StartSuccessor(listeners []Listener) (*os.Process, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]*os.File, 0, len(listeners))
+	names := make([]string, 0, len(listeners))
+	defer func() {
+		for _, file := range files {
+			file.Close() // #nosec G104
+		}
+	}()
+	for _, listener := range listeners {
+		filer, ok := listener.Socket.(interface{ File() (*os.File, error) })
+		if !ok {
+			return nil, fmt.Errorf("listener %s has no socket that can be handed over", listener.Name)
+		}
+		file, err := filer.File()
+		if err != nil {
+			return nil, fmt.Errorf("listener %s: %w", listener.Name, err)
+		}
+		files = append(files, file)
+		names = append(names, listener.Name)
+	}
+
+	env := make([]string, 0, len(os.Environ())+3)
+	for _, kv := range os.Environ() {
+		if !strings.HasPrefix(kv, "LISTEN_") {
+			env = append(env, kv)
+		}
+	}
+	env = append(env,
+		ListenParentPidEnv+"="+strconv.Itoa(os.Getpid()),
+		ListenFdsEnv+"="+strconv.Itoa(len(files)),
+		ListenFdNamesEnv+"="+strings.Join(names, ":"),
+	)
+
+	cmd := exec.Command(executable, os.Args[1:]...) // #nosec G204 -- re-executes ourselves
+	cmd.Env = env
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	cmd.ExtraFiles = files
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return cmd.Process, nil
+}
@@ -0,0 +1,221 @@
+package server
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// socketsOutEnv names the file the helper process reports what it inherited
+// to; the helper test does nothing without it.
+const socketsOutEnv = "PMGINGO_TEST_SOCKETS_OUT"
+
+type inheritedReport struct {
+	Addrs   map[string]string `json:"addrs"`
+	Err     string            `json:"err"`
+	EnvLeft []string          `json:"env_left"`
+}
+
+// TestInheritedListenersHelperProcess runs in a child process started by the
+// tests below, with the sockets under test as its file descriptors 3 and up.
+func TestInheritedListenersHelperProcess(t *testing.T) {
+	out := os.Getenv(socketsOutEnv)
+	if out == "" {
+		return
+	}
+	report := inheritedReport{Addrs: map[string]string{}}
+	listeners, err := InheritedListeners()
+	if err != nil {
+		report.Err = err.Error()
+	}
+	for name, listener := range listeners {
+		report.Addrs[name] = listener.Addr().String()
+		listener.Close() // #nosec G104
+	}
+	for _, env := range []string{ListenPidEnv, ListenParentPidEnv, ListenFdsEnv, ListenFdNamesEnv} {
+		if _, ok := os.LookupEnv(env); ok {
+			report.EnvLeft = append(report.EnvLeft, env)
+		}
+	}
+	content, _ := json.Marshal(report)
+	if err := os.WriteFile(out, content, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func listenFile(t *testing.T) (*os.File, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	file, err := ln.(*net.TCPListener).File()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file, ln.Addr().String()
+}
+
+func readReport(t *testing.T, out string) inheritedReport {
+	t.Helper()
+	content, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("helper process wrote no report: %v", err)
+	}
+	var report inheritedReport
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatal(err)
+	}
+	return report
+}
+
+// inherit runs the helper process with files as its inherited sockets and
+// env as its socket activation environment.
+func inherit(t *testing.T, files []*os.File, env map[string]string) inheritedReport {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "report.json")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestInheritedListenersHelperProcess$") // #nosec G204
+	cmd.Env = append(os.Environ(), socketsOutEnv+"="+out)
+	for key, val := range env {
+		cmd.Env = append(cmd.Env, key+"="+val)
+	}
+	cmd.ExtraFiles = files
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("helper process: %v\n%s", err, output)
+	}
+	return readReport(t, out)
+}
+
+func TestInheritedListeners(t *testing.T) {
+	public, publicAddr := listenFile(t)
+	admin, adminAddr := listenFile(t)
+	parent := strconv.Itoa(os.Getpid())
+
+	tests := []struct {
+		name    string
+		files   []*os.File
+		env     map[string]string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name:  "named sockets",
+			files: []*os.File{public, admin},
+			env:   map[string]string{ListenParentPidEnv: parent, ListenFdsEnv: "2", ListenFdNamesEnv: "public:admin"},
+			want:  map[string]string{"public": publicAddr, "admin": adminAddr},
+		},
+		{
+			name:  "unnamed socket",
+			files: []*os.File{public},
+			env:   map[string]string{ListenParentPidEnv: parent, ListenFdsEnv: "1"},
+			want:  map[string]string{UnnamedSocket: publicAddr},
+		},
+		{
+			name:  "addressed to another process",
+			files: []*os.File{public},
+			env:   map[string]string{ListenParentPidEnv: "1", ListenFdsEnv: "1"},
+			want:  map[string]string{},
+		},
+		{
+			name:    "names and count disagree",
+			files:   []*os.File{public},
+			env:     map[string]string{ListenParentPidEnv: parent, ListenFdsEnv: "1", ListenFdNamesEnv: "public:admin"},
+			wantErr: ListenFdNamesEnv + " names 2 sockets but " + ListenFdsEnv + " is 1",
+		},
+		{
+			name:    "invalid count",
+			files:   []*os.File{public},
+			env:     map[string]string{ListenParentPidEnv: parent, ListenFdsEnv: "one"},
+			wantErr: "invalid " + ListenFdsEnv,
+		},
+		{
+			name:    "duplicate names",
+			files:   []*os.File{public, admin},
+			env:     map[string]string{ListenParentPidEnv: parent, ListenFdsEnv: "2", ListenFdNamesEnv: "public:public"},
+			wantErr: `name "public" is used twice`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := inherit(t, tt.files, tt.env)
+			if tt.wantErr != "" {
+				if !strings.Contains(report.Err, tt.wantErr) {
+					t.Errorf("err = %q, want %q", report.Err, tt.wantErr)
+				}
+			} else if report.Err != "" {
+				t.Fatal(report.Err)
+			}
+			if tt.want != nil && !equalAddrs(report.Addrs, tt.want) {
+				t.Errorf("inherited %v, want %v", report.Addrs, tt.want)
+			}
+			if tt.env[ListenParentPidEnv] == parent && len(report.EnvLeft) > 0 {
+				t.Errorf("environment left set: %v", report.EnvLeft)
+			}
+		})
+	}
+}
+
+func TestInheritedListenersIgnoresAnotherPid(t *testing.T) {
+	t.Setenv(ListenPidEnv, strconv.Itoa(os.Getpid()+1))
+	t.Setenv(ListenFdsEnv, "1")
+
+	listeners, err := InheritedListeners()
+	if err != nil || len(listeners) != 0 {
+		t.Errorf("inherited %v, %v", listeners, err)
+	}
+	if os.Getenv(ListenFdsEnv) != "1" {
+		t.Error("environment of another process cleared")
+	}
+}
+
+func TestStartSuccessorHandsTheSocketsOver(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	out := filepath.Join(t.TempDir(), "report.json")
+	t.Setenv(socketsOutEnv, out)
+	args := os.Args
+	os.Args = []string{args[0], "-test.run=^TestInheritedListenersHelperProcess$"}
+	defer func() { os.Args = args }()
+
+	successor, err := StartSuccessor([]Listener{{Name: "public", Server: &http.Server{}, Socket: ln}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state, err := successor.Wait(); err != nil || !state.Success() {
+		t.Fatalf("successor: %v, %v", state, err)
+	}
+
+	report := readReport(t, out)
+	if report.Err != "" || !equalAddrs(report.Addrs, map[string]string{"public": ln.Addr().String()}) {
+		t.Errorf("successor inherited %v, %q", report.Addrs, report.Err)
+	}
+}
+
+func TestStartSuccessorNeedsBoundSockets(t *testing.T) {
+	if _, err := StartSuccessor([]Listener{{Name: "public", Server: &http.Server{}}}); err == nil {
+		t.Error("started a successor without sockets")
+	}
+}
+
+func equalAddrs(got map[string]string, want map[string]string) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for name, addr := range want {
+		if got[name] != addr {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const DefaultCertReloadInterval = 30 * time.Second
+
+// DefaultCipherSuites is the TLS 1.2 cipher policy: forward secret AEAD
+// suites only. TLS 1.3 suites are not configurable in crypto/tls.
+var DefaultCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// TLSConfig describes how a listener serves TLS. Setting ClientCAFile turns
+// on mutual TLS: client certificates are verified against that CA bundle.
+type TLSConfig struct {
+	CertFile string
+	KeyFile  string
+
+	// MinVersion defaults to tls.VersionTLS12.
+	MinVersion uint16
+	// CipherSuites defaults to DefaultCipherSuites.
+	CipherSuites []uint16
+
+	ClientCAFile string
+	// ClientAuth defaults to tls.RequireAndVerifyClientCert when ClientCAFile
+	// is set.
+	ClientAuth tls.ClientAuthType
+
+	// ReloadInterval is how often the certificate files are checked for
+	// changes. Defaults to DefaultCertReloadInterval; negative disables it.
+	ReloadInterval time.Duration
+}
+
+// Build loads the certificate and CA bundle and returns the tls.Config along
+// with the reloader serving the certificate.
+func (cfg TLSConfig) Build() (*tls.Config, *CertReloader, error) {
+	reloader, err := NewCertReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     cfg.MinVersion,
+		CipherSuites:   cfg.CipherSuites,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if tlsConfig.MinVersion == 0 {
+		tlsConfig.MinVersion = tls.VersionTLS12
+	}
+	if tlsConfig.CipherSuites == nil {
+		tlsConfig.CipherSuites = DefaultCipherSuites
+	}
+
+	if cfg.ClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.ClientCAFile)
+		if err != nil {
+			return nil, nil, fmt.Errorf("reading client CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, nil, fmt.Errorf("no certificates found in client CA bundle %s", cfg.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = cfg.ClientAuth
+		if tlsConfig.ClientAuth == tls.NoClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	return tlsConfig, reloader, nil
+}
+
+func (cfg TLSConfig) reloadInterval() time.Duration {
+	if cfg.ReloadInterval == 0 {
+		return DefaultCertReloadInterval
+	}
+	return cfg.ReloadInterval
+}
+
+// CertReloader serves a certificate/key pair from disk and swaps it in
+// place when the files change, without restarting the listener.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	cert atomic.Pointer[tls.Certificate]
+
+	mu      sync.Mutex
+	certMod time.Time
+	keyMod  time.Time
+}
+
+func NewCertReloader(certFile string, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the certificate files again. The current certificate is kept
+// when they cannot be loaded.
+func (r *CertReloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return err
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("loading certificate: %w", err)
+	}
+	r.cert.Store(&cert)
+	r.certMod, r.keyMod = certMod, keyMod
+	return nil
+}
+
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// Watch polls the certificate files every interval and reloads them when
+// either has been modified, until ctx is done.
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !r.changed() {
+				continue
+			}
+			if err := r.Reload(); err != nil {
+				logger.Error("tls certificate reload failed", "cert", r.certFile, "error", err)
+			} else {
+				logger.Info("tls certificate reloaded", "cert", r.certFile)
+			}
+		}
+	}
+}
+
+func (r *CertReloader) changed() bool {
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !certMod.Equal(r.certMod) || !keyMod.Equal(r.keyMod)
+}
+
+func (r *CertReloader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("reading certificate: %w", err)
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("reading key: %w", err)
+	}
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
@@ -0,0 +1,306 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PrathamSkilltelligent/pmgingo/routeutils"
+	"github.com/gin-gonic/gin"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCert issues a certificate for commonName, signed by issuer or
+// self-signed as a CA when issuer is nil.
+func newTestCert(t *testing.T, commonName string, issuer *testCert) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"pmgingo"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	parent, signer := template, key
+	if issuer == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func (c *testCert) tlsCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	cert, err := tls.X509KeyPair(c.certPEM, c.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// writePair writes the PEM files of cert and key, setting their modification
+// time to mod.
+func writePair(t *testing.T, certFile string, keyFile string, cert *testCert, key *testCert, mod time.Time) {
+	t.Helper()
+	for file, content := range map[string][]byte{certFile: cert.certPEM, keyFile: key.keyPEM} {
+		if err := os.WriteFile(file, content, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(file, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func servedSerial(t *testing.T, reloader *CertReloader) *big.Int {
+	t.Helper()
+	cert, err := reloader.GetCertificate(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.SerialNumber
+}
+
+// serveMutualTLS serves a handler reporting the client certificate subject
+// seen by RequestCtx with a tls.Config built from cfg.
+func serveMutualTLS(t *testing.T, cfg TLSConfig) string {
+	t.Helper()
+	tlsConfig, _, err := cfg.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.GET("/", func(c *gin.Context) {
+		subject := routeutils.NewRequestCtx(c).ClientCertSubject
+		if subject == nil {
+			c.String(http.StatusOK, "anonymous")
+			return
+		}
+		c.String(http.StatusOK, subject.CommonName)
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{
+		Handler:           engine,
+		ReadHeaderTimeout: time.Second,
+		// Rejected handshakes are expected.
+		ErrorLog: log.New(io.Discard, "", 0),
+	}
+	go server.Serve(tls.NewListener(ln, tlsConfig)) // #nosec G104
+	t.Cleanup(func() { _ = server.Close() })
+	return "https://" + ln.Addr().String()
+}
+
+func get(t *testing.T, url string, roots *x509.CertPool, clientCerts ...tls.Certificate) (string, error) {
+	t.Helper()
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      roots,
+		Certificates: clientCerts,
+		MinVersion:   tls.VersionTLS12,
+	}}}
+	defer client.CloseIdleConnections()
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body := make([]byte, 64)
+	n, _ := resp.Body.Read(body)
+	return string(body[:n]), nil
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, "test-ca", nil)
+	serverCert := newTestCert(t, "server", ca)
+	clientCert := newTestCert(t, "billing-service", ca)
+	strangerCert := newTestCert(t, "stranger", newTestCert(t, "other-ca", nil))
+
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	writePair(t, certFile, keyFile, serverCert, serverCert, time.Now())
+	if err := os.WriteFile(caFile, ca.certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	tests := []struct {
+		name       string
+		clientAuth tls.ClientAuthType
+		clientCert *testCert
+		want       string
+		wantErr    bool
+	}{
+		{"required with a certificate", 0, clientCert, "billing-service", false},
+		{"required without a certificate", 0, nil, "", true},
+		{"required with an unknown issuer", 0, strangerCert, "", true},
+		{"optional without a certificate", tls.VerifyClientCertIfGiven, nil, "anonymous", false},
+		{"optional with a certificate", tls.VerifyClientCertIfGiven, clientCert, "billing-service", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := serveMutualTLS(t, TLSConfig{
+				CertFile:     certFile,
+				KeyFile:      keyFile,
+				ClientCAFile: caFile,
+				ClientAuth:   tt.clientAuth,
+			})
+			var clientCerts []tls.Certificate
+			if tt.clientCert != nil {
+				clientCerts = append(clientCerts, tt.clientCert.tlsCertificate(t))
+			}
+
+			got, err := get(t, url, roots, clientCerts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("request succeeded as %q", got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("got %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildRejectsAnEmptyCABundle(t *testing.T) {
+	dir := t.TempDir()
+	cert := newTestCert(t, "server", nil)
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	writePair(t, certFile, keyFile, cert, cert, time.Now())
+	if err := os.WriteFile(caFile, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := (TLSConfig{CertFile: certFile, KeyFile: keyFile, ClientCAFile: caFile}).Build(); err == nil {
+		t.Error("empty CA bundle accepted")
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	first, second := newTestCert(t, "first", nil), newTestCert(t, "second", nil)
+	start := time.Now().Add(-time.Hour)
+	writePair(t, certFile, keyFile, first, first, start)
+
+	reloader, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloader.changed() {
+		t.Error("changed before any write")
+	}
+
+	// A certificate that does not match the key keeps the current pair.
+	writePair(t, certFile, keyFile, second, first, start.Add(time.Minute))
+	if !reloader.changed() {
+		t.Error("new files not noticed")
+	}
+	if err := reloader.Reload(); err == nil {
+		t.Error("mismatched pair loaded")
+	}
+	if servedSerial(t, reloader).Cmp(first.cert.SerialNumber) != 0 {
+		t.Error("mismatched pair replaced the served certificate")
+	}
+
+	writePair(t, certFile, keyFile, second, second, start.Add(2*time.Minute))
+	if err := reloader.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if servedSerial(t, reloader).Cmp(second.cert.SerialNumber) != 0 {
+		t.Error("new pair not served")
+	}
+	if reloader.changed() {
+		t.Error("changed after reloading")
+	}
+}
+
+func TestCertReloaderWatch(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	first, second := newTestCert(t, "first", nil), newTestCert(t, "second", nil)
+	start := time.Now().Add(-time.Hour)
+	writePair(t, certFile, keyFile, first, first, start)
+	reloader, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		reloader.Watch(ctx, 5*time.Millisecond, discardLogger)
+		close(done)
+	}()
+	writePair(t, certFile, keyFile, second, second, start.Add(time.Minute))
+
+	deadline := time.Now().Add(time.Second)
+	for servedSerial(t, reloader).Cmp(second.cert.SerialNumber) != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("new pair not picked up")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	<-done
+}